@@ -0,0 +1,59 @@
+package flow
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// adaptiveLimit bounds the limit of an adaptive LimitingExecutor.
+type adaptiveLimit struct {
+	min, max int
+}
+
+// AdaptingExecutor is a LimitingExecutor whose limit adapts to the load.
+//
+// The limit grows by one whenever a function is submitted while all running slots are taken, up to
+// the maximum, and shrinks by one whenever a function completes while nothing is queued, down to
+// the minimum. Once no function is running or queued anymore, the limit falls back to the minimum.
+type AdaptingExecutor struct {
+	*LimitingExecutor
+}
+
+// AdaptiveExecutor creates a new AdaptingExecutor whose limit starts at min and adapts between min and max.
+//
+// It panics if min is negative or max is less than min.
+func AdaptiveExecutor(min, max int, executor Executor) *AdaptingExecutor {
+	if min < 0 || max < min {
+		panic(fmt.Errorf("%w: limits must satisfy 0 <= min <= max but were %d and %d", ErrInvalidLimit, min, max))
+	}
+	return &AdaptingExecutor{&LimitingExecutor{
+		maxRunning: int64(min),
+		executor:   executor,
+		adaptive:   &adaptiveLimit{min, max},
+	}}
+}
+
+// Stats returns the current effective limit of the executor.
+func (e *AdaptingExecutor) Stats() (limit int) {
+	return e.limit()
+}
+
+// grow increases the limit by one if the executor is adaptive and below its maximum.
+func (p *LimitingExecutor) grow() {
+	if a := p.adaptive; a != nil && p.limit() < a.max {
+		atomic.AddInt64(&p.maxRunning, 1)
+	}
+}
+
+// shrink decreases the limit by one if the executor is adaptive and above its minimum.
+// If the executor is idle, the limit is reset to its minimum.
+func (p *LimitingExecutor) shrink(idle bool) {
+	a := p.adaptive
+	switch {
+	case a == nil:
+	case idle:
+		atomic.StoreInt64(&p.maxRunning, int64(a.min))
+	case p.limit() > a.min:
+		atomic.AddInt64(&p.maxRunning, -1)
+	}
+}
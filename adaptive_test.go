@@ -0,0 +1,40 @@
+package flow_test
+
+import (
+	"sync"
+
+	"github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AdaptingExecutor", func() {
+	It("should raise the limit during a burst and lower it once the burst drained", func() {
+		var (
+			ex      = flow.AdaptiveExecutor(1, 4, flow.UnlimitedExecutor)
+			release = make(chan struct{})
+			wg      sync.WaitGroup
+		)
+		ex.Start()
+		defer ex.Stop()
+		Expect(ex.Stats()).To(Equal(1))
+
+		wg.Add(10)
+		for i := 0; i < 10; i++ {
+			ex.Submit(func() {
+				defer wg.Done()
+				<-release
+			})
+		}
+		Eventually(ex.Stats).Should(Equal(4))
+
+		close(release)
+		wg.Wait()
+		Eventually(ex.Stats).Should(Equal(1))
+	})
+
+	It("should panic on invalid limits", func() {
+		Expect(func() { flow.AdaptiveExecutor(2, 1, flow.UnlimitedExecutor) }).To(Panic())
+		Expect(func() { flow.AdaptiveExecutor(-1, 1, flow.UnlimitedExecutor) }).To(Panic())
+	})
+})
@@ -0,0 +1,223 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrDependencyCycle is returned by DAG.Run if the nodes of the DAG depend on each other in a cycle.
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrUnknownDependency is returned by DAG.Run if a node depends on a node that was not added.
+	ErrUnknownDependency = errors.New("unknown dependency")
+)
+
+// DAG is a directed acyclic graph of functions that depend on each other.
+//
+// Functions are added as named nodes that depend on the names of their predecessors. The nodes
+// may be added in any order; the dependencies are validated once the DAG is run. The zero value
+// is an empty DAG ready to use. A DAG must not be modified while it runs.
+type DAG struct {
+	nodes  []*DAGNode
+	byName map[string]*DAGNode
+}
+
+// DAGNode is a function registered in a DAG.
+type DAGNode struct {
+	index int
+	name  string
+	deps  []string
+	// run runs the function of the node with the values of its dependencies.
+	run func(ctx context.Context, deps map[string]interface{}) (interface{}, error)
+	// value indicates whether the node produces a value for its dependents.
+	value bool
+}
+
+// Name returns the name of the node.
+func (n *DAGNode) Name() string {
+	return n.name
+}
+
+// Add registers fn as node with the given name that is run once the nodes named deps succeeded.
+//
+// Adding two nodes with the same name panics.
+func (d *DAG) Add(name string, fn Func, deps ...string) *DAGNode {
+	return d.add(name, func(ctx context.Context, _ map[string]interface{}) (interface{}, error) {
+		if fn == nil {
+			return nil, nil
+		}
+		return nil, fn(ctx)
+	}, false, deps)
+}
+
+// AddValue registers fn as node of d with the given name that is run once the nodes named deps
+// succeeded, and whose result is passed to the nodes depending on it.
+//
+// fn is called with the results of the dependencies that were added with AddValue, keyed by
+// their names. The results of the other dependencies are not contained. Adding two nodes with the
+// same name panics.
+func AddValue[T any](d *DAG, name string, fn func(ctx context.Context, deps map[string]interface{}) (T, error), deps ...string) *DAGNode {
+	return d.add(name, func(ctx context.Context, values map[string]interface{}) (interface{}, error) {
+		return fn(ctx, values)
+	}, true, deps)
+}
+
+// add registers a node running run. If value is true, the result of run is passed to the dependents.
+func (d *DAG) add(name string, run func(context.Context, map[string]interface{}) (interface{}, error), value bool, deps []string) *DAGNode {
+	if _, ok := d.byName[name]; ok {
+		panic(fmt.Errorf("duplicate node %q", name))
+	}
+	if d.byName == nil {
+		d.byName = make(map[string]*DAGNode)
+	}
+
+	n := &DAGNode{index: len(d.nodes), name: name, deps: deps, run: run, value: value}
+	d.nodes = append(d.nodes, n)
+	d.byName[name] = n
+	return n
+}
+
+// validate returns an error if a node depends on an unknown node or if the nodes depend on each
+// other in a cycle.
+func (d *DAG) validate() error {
+	for _, n := range d.nodes {
+		for _, dep := range n.deps {
+			if _, ok := d.byName[dep]; !ok {
+				return fmt.Errorf("%w: node %q depends on %q", ErrUnknownDependency, n.name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var (
+		state = make([]int, len(d.nodes))
+		path  []string
+		visit func(n *DAGNode) error
+	)
+	visit = func(n *DAGNode) error {
+		switch state[n.index] {
+		case visited:
+			return nil
+		case visiting:
+			for i, name := range path {
+				if name == n.name {
+					return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(append(path[i:], n.name), " -> "))
+				}
+			}
+		}
+
+		state[n.index] = visiting
+		path = append(path, n.name)
+		for _, dep := range n.deps {
+			if err := visit(d.byName[dep]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[n.index] = visited
+		return nil
+	}
+	for _, n := range d.nodes {
+		if err := visit(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run runs the nodes of the DAG on the Executor of f, each once all of its dependencies succeeded.
+//
+// Nodes that do not depend on each other are run in parallel. If a node fails, the nodes depending
+// on it, directly or transitively, are skipped while the other nodes keep running. Run returns
+// once all nodes completed or were skipped and collects the errors of the failed nodes in the
+// returned error. To obtain the multiple errors, use the `Errors` function.
+//
+// If the dependencies are invalid, Run returns an error wrapping ErrUnknownDependency or
+// ErrDependencyCycle before running any node.
+func (d *DAG) Run(ctx context.Context, f *Flow) error {
+	_, err := d.RunValues(ctx, f)
+	return err
+}
+
+// RunValues runs the DAG like Run and additionally returns the results of the succeeded nodes that
+// were added with AddValue, keyed by their names.
+func (d *DAG) RunValues(ctx context.Context, f *Flow) (map[string]interface{}, error) {
+	if len(d.nodes) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(d.nodes)); err != nil {
+		return nil, err
+	}
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		pending    = make([]int, len(d.nodes))
+		dependents = make([][]int, len(d.nodes))
+		results    = make(chan result[interface{}], len(d.nodes))
+		executor   = f.executorFor(ctx)
+		running    int
+		// values is only accessed by the calling goroutine; the nodes receive copies of the values
+		// of their dependencies.
+		values = make(map[string]interface{})
+	)
+	submit := func(n *DAGNode) {
+		deps := make(map[string]interface{}, len(n.deps))
+		for _, dep := range n.deps {
+			if value, ok := values[dep]; ok {
+				deps[dep] = value
+			}
+		}
+
+		running++
+		done := f.track()
+		executor.Submit(func() {
+			// A node whose function panics is reported as failed, so that Run does not wait for it
+			// forever if the Executor recovers the panic.
+			res := result[interface{}]{index: n.index, err: fmt.Errorf("node %q did not complete", n.name)}
+			defer func() {
+				done()
+				results <- res
+			}()
+			res.item, res.err = n.run(f.withBatchInfo(ctx, n.index, len(d.nodes)), deps)
+		})
+	}
+
+	for _, n := range d.nodes {
+		pending[n.index] = len(n.deps)
+		for _, dep := range n.deps {
+			dependents[d.byName[dep].index] = append(dependents[d.byName[dep].index], n.index)
+		}
+	}
+	for _, n := range d.nodes {
+		if pending[n.index] == 0 {
+			submit(n)
+		}
+	}
+
+	var errs multiError
+	for running > 0 {
+		res := <-results
+		running--
+		if res.err != nil {
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+		if n := d.nodes[res.index]; n.value {
+			values[n.name] = res.item
+		}
+		for _, i := range dependents[res.index] {
+			if pending[i]--; pending[i] == 0 {
+				submit(d.nodes[i])
+			}
+		}
+	}
+	return values, f.aggregate(errs)
+}
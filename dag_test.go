@@ -0,0 +1,143 @@
+package flow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DAG", func() {
+	var (
+		lock  sync.Mutex
+		order []string
+	)
+	BeforeEach(func() {
+		order = nil
+	})
+	record := func(name string) Func {
+		return func(context.Context) error {
+			lock.Lock()
+			defer lock.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+	indexOf := func(name string) int {
+		for i, n := range order {
+			if n == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	It("should run the nodes after their dependencies", func() {
+		var dag DAG
+		dag.Add("d", record("d"), "b", "c")
+		dag.Add("b", record("b"), "a")
+		dag.Add("c", record("c"), "a")
+		dag.Add("a", record("a"))
+
+		Expect(dag.Run(context.TODO(), New(UnlimitedExecutor))).To(Succeed())
+		Expect(order).To(ConsistOf("a", "b", "c", "d"))
+		Expect(indexOf("a")).To(BeNumerically("<", indexOf("b")))
+		Expect(indexOf("a")).To(BeNumerically("<", indexOf("c")))
+		Expect(indexOf("b")).To(BeNumerically("<", indexOf("d")))
+		Expect(indexOf("c")).To(BeNumerically("<", indexOf("d")))
+	})
+
+	It("should run independent nodes in parallel", func() {
+		var (
+			dag     DAG
+			started = make(chan struct{})
+		)
+		dag.Add("a", func(context.Context) error {
+			<-started
+			return nil
+		})
+		dag.Add("b", func(context.Context) error {
+			close(started)
+			return nil
+		})
+
+		Expect(dag.Run(context.TODO(), New(UnlimitedExecutor))).To(Succeed())
+	})
+
+	It("should skip the nodes depending on a failed node", func() {
+		var (
+			dag  DAG
+			err1 = mkError(1)
+		)
+		dag.Add("a", func(context.Context) error { return err1 })
+		dag.Add("b", record("b"), "a")
+		dag.Add("c", record("c"), "b")
+		dag.Add("d", record("d"))
+
+		err := dag.Run(context.TODO(), New(UnlimitedExecutor))
+		Expect(Errors(err)).To(ConsistOf(err1))
+		Expect(order).To(ConsistOf("d"))
+	})
+
+	It("should pass the values of the dependencies to a node", func() {
+		var dag DAG
+		constant := func(n int) func(context.Context, map[string]interface{}) (int, error) {
+			return func(context.Context, map[string]interface{}) (int, error) { return n, nil }
+		}
+		AddValue(&dag, "a", constant(1))
+		AddValue(&dag, "b", constant(2))
+		dag.Add("c", record("c"))
+		AddValue(&dag, "sum", func(ctx context.Context, deps map[string]interface{}) (int, error) {
+			Expect(deps).To(HaveLen(2))
+			return deps["a"].(int) + deps["b"].(int), nil
+		}, "a", "b", "c")
+
+		values, err := dag.RunValues(context.TODO(), New(UnlimitedExecutor))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(Equal(map[string]interface{}{"a": 1, "b": 2, "sum": 3}))
+	})
+
+	It("should not return the values of failed nodes", func() {
+		var (
+			dag  DAG
+			err1 = mkError(1)
+		)
+		AddValue(&dag, "a", func(context.Context, map[string]interface{}) (string, error) { return "a", nil })
+		AddValue(&dag, "b", func(context.Context, map[string]interface{}) (string, error) { return "", err1 }, "a")
+
+		values, err := dag.RunValues(context.TODO(), New(UnlimitedExecutor))
+		Expect(Errors(err)).To(ConsistOf(err1))
+		Expect(values).To(Equal(map[string]interface{}{"a": "a"}))
+	})
+
+	It("should detect cycles before running any node", func() {
+		var dag DAG
+		dag.Add("a", record("a"))
+		dag.Add("b", record("b"), "a", "d")
+		dag.Add("c", record("c"), "b")
+		dag.Add("d", record("d"), "c")
+
+		err := dag.Run(context.TODO(), New(UnlimitedExecutor))
+		Expect(errors.Is(err, ErrDependencyCycle)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("b -> d -> c -> b")))
+		Expect(order).To(BeEmpty())
+	})
+
+	It("should reject unknown dependencies", func() {
+		var dag DAG
+		dag.Add("a", record("a"), "b")
+
+		err := dag.Run(context.TODO(), New(UnlimitedExecutor))
+		Expect(errors.Is(err, ErrUnknownDependency)).To(BeTrue())
+		Expect(order).To(BeEmpty())
+	})
+
+	It("should panic when adding a node with a name that is already taken", func() {
+		var dag DAG
+		dag.Add("a", record("a"))
+		Expect(func() { dag.Add("a", record("a")) }).To(Panic())
+	})
+})
@@ -0,0 +1,46 @@
+package flow
+
+import (
+	"context"
+	"sync"
+)
+
+// Deferred collects cleanup functions to run them in reverse order, like deferred calls.
+//
+// The zero value is ready to use. It is safe to add functions concurrently.
+type Deferred struct {
+	lock sync.Mutex
+	fns  []Func
+}
+
+// Add registers fn to be run by Run before all previously added functions.
+func (d *Deferred) Add(fn Func) {
+	if fn == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.fns = append(d.fns, fn)
+}
+
+// Run runs the added functions one after another in reverse order of their addition.
+//
+// All functions are run, even if some of them fail or the context expires; it is up to the
+// functions to observe the context. The functions are removed, so a subsequent Run only runs
+// functions added in the meantime. It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (d *Deferred) Run(ctx context.Context) error {
+	d.lock.Lock()
+	fns := d.fns
+	d.fns = nil
+	d.lock.Unlock()
+
+	var errs multiError
+	for i := len(fns) - 1; i >= 0; i-- {
+		if err := fns[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,68 @@
+package flow_test
+
+import (
+	"context"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deferred", func() {
+	It("should run the added functions in reverse order", func() {
+		var (
+			d     Deferred
+			order []int
+		)
+		for i := 0; i < 3; i++ {
+			i := i
+			d.Add(func(ctx context.Context) error {
+				order = append(order, i)
+				return nil
+			})
+		}
+
+		Expect(d.Run(context.TODO())).To(Succeed())
+		Expect(order).To(Equal([]int{2, 1, 0}))
+	})
+
+	It("should run all functions despite failures and collect the errors", func() {
+		var (
+			d     Deferred
+			order []int
+			err1  = mkError(1)
+			err2  = mkError(2)
+		)
+		d.Add(func(ctx context.Context) error {
+			order = append(order, 0)
+			return nil
+		})
+		d.Add(func(ctx context.Context) error {
+			order = append(order, 1)
+			return err1
+		})
+		d.Add(func(ctx context.Context) error {
+			order = append(order, 2)
+			return err2
+		})
+
+		err := d.Run(context.TODO())
+		Expect(Errors(err)).To(Equal([]error{err2, err1}))
+		Expect(order).To(Equal([]int{2, 1, 0}))
+	})
+
+	It("should not run the functions again on a subsequent run", func() {
+		var (
+			d    Deferred
+			runs int
+		)
+		d.Add(func(ctx context.Context) error {
+			runs++
+			return nil
+		})
+
+		Expect(d.Run(context.TODO())).To(Succeed())
+		Expect(d.Run(context.TODO())).To(Succeed())
+		Expect(runs).To(Equal(1))
+	})
+})
@@ -0,0 +1,55 @@
+package flow
+
+import "time"
+
+// WithDrainTimeout makes the race-style operations of the Flow wait at most d for the remaining functions.
+//
+// Operations like Race return once a result was decided, but by default they wait for the
+// cancelled remaining functions to complete, so a function ignoring its context blocks the caller.
+// With a drain timeout, the operations return after d at the latest and the remaining functions
+// continue running detached from the caller. Results that still need cleanup, like the losers of
+// RaceCloser, are then cleaned up by a separate goroutine. If d is not positive, the operations
+// wait for all remaining functions.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(f *Flow) {
+		f.drainTimeout = d
+	}
+}
+
+// drain receives the remaining results until results is closed, passing every result to discard if
+// it is not nil. Once the drain timeout of the Flow elapsed, drain returns and the remaining results
+// are received by a separate goroutine, so that functions sending to an unbuffered results channel
+// do not block forever.
+func drain[T any](f *Flow, results <-chan T, discard func(T)) {
+	if f.drainTimeout <= 0 {
+		for res := range results {
+			if discard != nil {
+				discard(res)
+			}
+		}
+		return
+	}
+
+	timer := time.NewTimer(f.drainTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				return
+			}
+			if discard != nil {
+				discard(res)
+			}
+		case <-timer.C:
+			go func() {
+				for res := range results {
+					if discard != nil {
+						discard(res)
+					}
+				}
+			}()
+			return
+		}
+	}
+}
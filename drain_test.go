@@ -0,0 +1,86 @@
+package flow_test
+
+import (
+	"context"
+	"io"
+	"time"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drain", func() {
+	Describe("WithDrainTimeout", func() {
+		var (
+			release chan struct{}
+			f       *Flow
+		)
+		BeforeEach(func() {
+			release = make(chan struct{})
+			f = New(UnlimitedExecutor, WithDrainTimeout(10*time.Millisecond))
+		})
+		AfterEach(func() {
+			close(release)
+		})
+
+		It("should return the winner of Race although a function ignores the cancellation", func() {
+			var (
+				err1    = mkError(1)
+				release = release
+			)
+
+			done := make(chan error, 1)
+			go func() {
+				done <- f.Race(context.TODO(),
+					func(ctx context.Context) error { return err1 },
+					func(ctx context.Context) error {
+						<-release
+						return nil
+					},
+				)
+			}()
+			Eventually(done).Should(Receive(BeIdenticalTo(err1)))
+		})
+
+		It("should close the losers of RaceCloser once they complete after the timeout", func() {
+			var (
+				winner  = &countingCloser{}
+				loser   = &countingCloser{}
+				release = release
+			)
+
+			res, err := f.RaceCloser(context.TODO(),
+				func(ctx context.Context) (io.Closer, error) { return winner, nil },
+				func(ctx context.Context) (io.Closer, error) {
+					<-release
+					return loser, nil
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeIdenticalTo(winner))
+
+			release <- struct{}{}
+			Eventually(loser.Closed).Should(Equal(int32(1)))
+			Expect(winner.Closed()).To(BeZero())
+		})
+
+		It("should let the abandoned functions of SearchBool complete after the timeout", func() {
+			release := release
+
+			ok, err := f.SearchBool(context.TODO(), 2,
+				func(ctx context.Context) (bool, error) { return true, nil },
+				func(ctx context.Context) (bool, error) {
+					<-release
+					return false, nil
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(f.InFlight()).To(Equal(1))
+
+			release <- struct{}{}
+			Eventually(f.InFlight).Should(BeZero())
+		})
+	})
+})
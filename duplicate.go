@@ -0,0 +1,36 @@
+package flow
+
+import "reflect"
+
+// WarnOnDuplicateFuncs makes Parallel and ParallelOn report functions that are passed more than once.
+//
+// Passing the same function twice is legal, but often an accident leading to doubled side effects.
+// Before running the functions, logf is called once for every function that is identical to a
+// function at a lower index. Functions are identical if they share their code, so besides copies of
+// the same function value, closures created from the same function literal are reported as well.
+func WarnOnDuplicateFuncs(logf func(format string, args ...interface{})) Option {
+	return func(f *Flow) {
+		f.warnDuplicate = logf
+	}
+}
+
+// warnDuplicates reports the duplicates among fns if the Flow has a warning function.
+func (f *Flow) warnDuplicates(fns []Func) {
+	if f.warnDuplicate == nil {
+		return
+	}
+
+	seen := make(map[uintptr]int, len(fns))
+	for i, fn := range fns {
+		if fn == nil {
+			continue
+		}
+
+		p := reflect.ValueOf(fn).Pointer()
+		if first, ok := seen[p]; ok {
+			f.warnDuplicate("flow: function at index %d is a duplicate of the function at index %d", i, first)
+			continue
+		}
+		seen[p] = i
+	}
+}
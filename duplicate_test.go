@@ -0,0 +1,59 @@
+package flow_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Duplicate", func() {
+	Describe("WarnOnDuplicateFuncs", func() {
+		var (
+			warnings []string
+			f        *Flow
+		)
+		BeforeEach(func() {
+			warnings = nil
+			f = New(SyncExecutor, WarnOnDuplicateFuncs(func(format string, args ...interface{}) {
+				warnings = append(warnings, fmt.Sprintf(format, args...))
+			}))
+		})
+
+		It("should warn once about a function passed twice and still run it twice", func() {
+			var calls int
+			fn := func(context.Context) error {
+				calls++
+				return nil
+			}
+
+			Expect(f.Parallel(context.TODO(), fn, nil, fn)).To(Succeed())
+			Expect(warnings).To(Equal([]string{"flow: function at index 2 is a duplicate of the function at index 0"}))
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should warn about closures of the same literal", func() {
+			fns := make([]Func, 2)
+			for i := range fns {
+				i := i
+				fns[i] = func(context.Context) error {
+					_ = i
+					return nil
+				}
+			}
+
+			Expect(f.Parallel(context.TODO(), fns...)).To(Succeed())
+			Expect(warnings).To(Equal([]string{"flow: function at index 1 is a duplicate of the function at index 0"}))
+		})
+
+		It("should not warn about distinct functions", func() {
+			Expect(f.Parallel(context.TODO(),
+				func(context.Context) error { return nil },
+				func(context.Context) error { return nil },
+			)).To(Succeed())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+})
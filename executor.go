@@ -1,8 +1,13 @@
 package flow
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime/pprof"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Executor allows non-blocking submission of functions.
@@ -11,6 +16,48 @@ type Executor interface {
 	Submit(f func())
 }
 
+// ExecutorFunc is an adapter to allow the use of ordinary functions as Executor.
+type ExecutorFunc func(f func())
+
+// Submit calls e(f).
+func (e ExecutorFunc) Submit(f func()) {
+	e(f)
+}
+
+// WaitableExecutor is an Executor that signals the completion of submitted functions.
+type WaitableExecutor interface {
+	Executor
+	// SubmitWait schedules f like Submit and returns a channel that is closed once f completed.
+	SubmitWait(f func()) <-chan struct{}
+}
+
+// submitWait implements WaitableExecutor.SubmitWait on top of executor.
+func submitWait(executor Executor, f func()) <-chan struct{} {
+	done := make(chan struct{})
+	executor.Submit(func() {
+		defer close(done)
+		f()
+	})
+	return done
+}
+
+type waitExecutor struct {
+	Executor
+}
+
+// WaitExecutor makes inner a WaitableExecutor. If inner already is a WaitableExecutor, it is returned as is.
+func WaitExecutor(inner Executor) WaitableExecutor {
+	if w, ok := inner.(WaitableExecutor); ok {
+		return w
+	}
+	return waitExecutor{inner}
+}
+
+// SubmitWait implements WaitableExecutor.
+func (e waitExecutor) SubmitWait(f func()) <-chan struct{} {
+	return submitWait(e.Executor, f)
+}
+
 type plainExecutor struct{}
 
 func (plainExecutor) Submit(f func()) {
@@ -20,76 +67,382 @@ func (plainExecutor) Submit(f func()) {
 // UnlimitedExecutor is an Executor that dispatches every function immediately with `go func()`.
 var UnlimitedExecutor Executor = plainExecutor{}
 
+type syncExecutor struct{}
+
+func (syncExecutor) Submit(f func()) {
+	f()
+}
+
+// SyncExecutor is an Executor that runs every function immediately on the submitting goroutine.
+//
+// A Flow using the SyncExecutor runs the given functions one after another in input order, which
+// makes its results deterministic, e.g. Race always yields the result of the first function.
+// This is mostly useful for tests. Note that functions waiting for their siblings (e.g. for the
+// cancellation by Race) block forever with this Executor.
+var SyncExecutor Executor = syncExecutor{}
+
+type executorKey struct{}
+
+// ContextWithExecutor returns a copy of ctx that carries the given Executor.
+//
+// Operations of the Default flow (and thus the package-level functions) running with such a context
+// submit their functions to the carried Executor instead of the UnlimitedExecutor.
+func ContextWithExecutor(ctx context.Context, executor Executor) context.Context {
+	return context.WithValue(ctx, executorKey{}, executor)
+}
+
+// ExecutorFromContext returns the Executor carried by ctx, if any.
+func ExecutorFromContext(ctx context.Context) (Executor, bool) {
+	executor, ok := ctx.Value(executorKey{}).(Executor)
+	return executor, ok
+}
+
 // LimitingExecutor represents a pool of goroutines.
+//
+// Submitted functions are started in the order they were submitted. Functions submitted
+// concurrently are ordered by the time the executor accepted them.
 type LimitingExecutor struct {
-	maxRunning int
+	maxRunning int64 // accessed atomically
 	executor   Executor
-	lock       sync.Mutex
+	lock       sync.RWMutex
 
-	running bool
+	// adaptive makes the limit adapt to the load, if set.
+	adaptive *adaptiveLimit
+	// prewarm makes the executor run its functions on persistent workers, if set.
+	prewarm bool
+
+	// ingest passes submitted functions to the scheduler until quit is closed by Stop. Both are nil
+	// while the executor is not started.
 	ingest  chan<- func()
+	quit    chan struct{}
+	stopped <-chan struct{}
+
+	pending pendingTracker
 }
 
-// LimitExecutor creates a new Executor with the given maximum number of goroutines that may run simultaneously.
-func LimitExecutor(limit int, executor Executor) *LimitingExecutor {
+// ErrInvalidLimit is returned by NewLimitExecutor if the given limit is negative.
+var ErrInvalidLimit = errors.New("invalid limit")
+
+// LimitOption configures a LimitingExecutor.
+type LimitOption func(*LimitingExecutor)
+
+// WithPrewarm makes the LimitingExecutor run its functions on a fixed pool of persistent workers.
+//
+// When started, the executor submits as many workers as its limit to the underlying Executor.
+// The workers block waiting for functions, so that bursts of submissions do not pay for spawning
+// goroutines, at the cost of keeping the workers alive until the executor is stopped. The underlying
+// Executor has to run the workers asynchronously.
+func WithPrewarm() LimitOption {
+	return func(p *LimitingExecutor) {
+		p.prewarm = true
+	}
+}
+
+// NewLimitExecutor creates a new Executor with the given maximum number of goroutines that may run simultaneously.
+//
+// If limit is negative, an error wrapping ErrInvalidLimit is returned. A limit of 0 is valid but
+// never runs any submitted function, so anything waiting for those functions blocks forever.
+func NewLimitExecutor(limit int, executor Executor, opts ...LimitOption) (*LimitingExecutor, error) {
 	if limit < 0 {
-		panic(fmt.Errorf("limit may not be < 0 but was %d", limit))
+		return nil, fmt.Errorf("%w: limit may not be < 0 but was %d", ErrInvalidLimit, limit)
 	}
-	return &LimitingExecutor{maxRunning: limit, executor: executor}
+	p := &LimitingExecutor{maxRunning: int64(limit), executor: executor}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// LimitExecutor creates a new Executor with the given maximum number of goroutines that may run simultaneously.
+//
+// It panics if limit is negative. See NewLimitExecutor for a variant returning an error instead.
+func LimitExecutor(limit int, executor Executor, opts ...LimitOption) *LimitingExecutor {
+	ex, err := NewLimitExecutor(limit, executor, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return ex
 }
 
 // Start launches the pool, making it ready to accept submissions.
+//
+// A stopped executor may be started again. Functions of the previous run that are still running
+// are not counted against the limit of the new run, and Done then refers to the new run.
 func (p *LimitingExecutor) Start() {
+	p.StartContext(context.Background())
+}
+
+// StartContext launches the pool, making it ready to accept submissions until ctx is done.
+//
+// Once ctx is done, the executor is stopped as if Stop was called. Executors that are neither
+// stopped nor started with a context that is eventually done leak their scheduling goroutine.
+func (p *LimitingExecutor) StartContext(ctx context.Context) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	if p.ingest == nil {
-		var (
-			ingest = make(chan func())
-			queue  []func()
-		)
-		p.ingest = ingest
+	if p.ingest != nil {
+		return
+	}
+
+	var (
+		ingest  = make(chan func())
+		quit    = make(chan struct{})
+		stopped = make(chan struct{})
+	)
+	p.ingest, p.quit, p.stopped = ingest, quit, stopped
+	if p.prewarm {
+		go p.scheduleWorkers(ingest, quit, stopped)
+	} else {
+		go p.schedule(ingest, quit, stopped)
+	}
+
+	if done := ctx.Done(); done != nil {
 		go func() {
-			var (
-				current int
-				wg      sync.WaitGroup
-				done    = make(chan struct{})
-			)
-			defer close(done)
-
-		Loop:
-			for {
-				select {
-				case <-done:
-					current--
-				case f, ok := <-ingest:
-					if !ok {
-						break Loop
-					}
-					queue = append(queue, f)
-				default:
-					if len(queue) > 0 && current < p.maxRunning {
-						current++
-						f := queue[0]
-						queue = queue[1:]
-						wg.Add(1)
-						p.executor.Submit(func() {
-							defer wg.Done()
-							f()
-							done <- struct{}{}
-						})
-					}
+			select {
+			case <-done:
+				p.lock.Lock()
+				defer p.lock.Unlock()
+				if p.quit == quit {
+					p.stop()
 				}
+			case <-stopped:
 			}
-
-			wg.Wait()
 		}()
 	}
 }
 
+// schedule runs the submitted functions until quit is closed, closing stopped afterwards.
+func (p *LimitingExecutor) schedule(ingest <-chan func(), quit <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	// Every running function sends exactly one completion, so buffering done for the largest possible
+	// number of running functions keeps underlying Executors that run functions synchronously on the
+	// scheduling goroutine from blocking it.
+	size := p.limit()
+	if a := p.adaptive; a != nil && a.max > size {
+		size = a.max
+	}
+
+	var (
+		current int
+		queue   []func()
+		wg      sync.WaitGroup
+		done    = make(chan struct{}, size)
+	)
+
+Loop:
+	for {
+		// Functions are only ever taken from the front of the queue, so they are started strictly in
+		// the order they were submitted.
+		for len(queue) > 0 && current < p.limit() {
+			current++
+			f := queue[0]
+			queue[0] = nil
+			queue = queue[1:]
+			wg.Add(1)
+			p.executor.Submit(func() {
+				defer wg.Done()
+				f()
+				done <- struct{}{}
+			})
+		}
+
+		select {
+		case <-done:
+			current--
+			if len(queue) == 0 {
+				p.shrink(current == 0)
+			}
+		case f := <-ingest:
+			queue = append(queue, f)
+			if current >= p.limit() {
+				p.grow()
+			}
+		case <-quit:
+			break Loop
+		}
+	}
+
+	p.pending.add(-len(queue))
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for range done {
+	}
+}
+
+// scheduleWorkers runs the submitted functions on persistent workers until quit is closed,
+// closing stopped afterwards.
+func (p *LimitingExecutor) scheduleWorkers(ingest <-chan func(), quit <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	var (
+		queue []func()
+		work  = make(chan func())
+		wg    sync.WaitGroup
+	)
+	defer wg.Wait()
+	defer close(work)
+
+	n := p.limit()
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		p.executor.Submit(func() {
+			defer wg.Done()
+			for f := range work {
+				f()
+			}
+		})
+	}
+
+	for {
+		var (
+			next func()
+			out  chan<- func()
+		)
+		if len(queue) > 0 {
+			next, out = queue[0], work
+		}
+
+		select {
+		case f := <-ingest:
+			queue = append(queue, f)
+		case <-quit:
+			p.pending.add(-len(queue))
+			return
+		case out <- next:
+			queue = queue[1:]
+		}
+	}
+}
+
+// pendingTracker counts the functions that were submitted but did not complete yet.
+type pendingTracker struct {
+	lock  sync.Mutex
+	count int
+	// idle is closed once count drops to zero. It is nil while count is zero.
+	idle chan struct{}
+}
+
+// add adds n to the number of pending functions.
+func (t *pendingTracker) add(n int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.count == 0 && n > 0 {
+		t.idle = make(chan struct{})
+	}
+	t.count += n
+	if t.count == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// track wraps f to remove it from the pending functions once it completed.
+func (t *pendingTracker) track(f func()) func() {
+	return func() {
+		defer t.add(-1)
+		f()
+	}
+}
+
+// wait blocks until there are no pending functions or ctx is done.
+func (t *pendingTracker) wait(ctx context.Context) error {
+	t.lock.Lock()
+	idle := t.idle
+	t.lock.Unlock()
+
+	if idle == nil {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limit returns the current maximum number of goroutines that may run simultaneously.
+func (p *LimitingExecutor) limit() int {
+	return int(atomic.LoadInt64(&p.maxRunning))
+}
+
+// ErrNotStarted is the panic value of submissions to a LimitingExecutor that is not started and to
+// stopped executors.
+var ErrNotStarted = errors.New("executor not started")
+
 // Submit schedules f to be executed in a non-blocking way.
+//
+// It panics with ErrNotStarted if the executor was not started or is stopped. If the executor is
+// stopped while Submit waits for the scheduler, f is dropped like the queued functions.
 func (p *LimitingExecutor) Submit(f func()) {
-	p.ingest <- f
+	ingest, quit := p.channels()
+	if ingest == nil {
+		panic(ErrNotStarted)
+	}
+
+	p.pending.add(1)
+	select {
+	case ingest <- p.pending.track(f):
+	case <-quit:
+		p.pending.add(-1)
+	}
+}
+
+// channels returns the channels of the current run, or nil if the executor is not started.
+//
+// The channels are read under the lock but used without holding it, so that Stop does not have to
+// wait for submissions blocked by the scheduler.
+func (p *LimitingExecutor) channels() (ingest chan<- func(), quit <-chan struct{}) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.ingest, p.quit
+}
+
+// SubmitWait schedules f like Submit and returns a channel that is closed once f completed.
+//
+// It panics with ErrNotStarted if the executor was not started or is stopped. If f is dropped
+// because the executor is stopped before running it, the channel is never closed.
+func (p *LimitingExecutor) SubmitWait(f func()) <-chan struct{} {
+	return submitWait(p, f)
+}
+
+// SubmitContext schedules f to be executed like Submit, giving up once ctx is done.
+//
+// If the executor does not accept f before ctx is done, for example because its scheduler is
+// blocked by the underlying Executor, the context error is returned. If the executor was not
+// started or is stopped, including while SubmitContext waits for the scheduler, ErrNotStarted is
+// returned instead of panicking.
+func (p *LimitingExecutor) SubmitContext(ctx context.Context, f func()) error {
+	ingest, quit := p.channels()
+	if ingest == nil {
+		return ErrNotStarted
+	}
+
+	p.pending.add(1)
+	select {
+	case ingest <- p.pending.track(f):
+		return nil
+	case <-quit:
+		p.pending.add(-1)
+		return ErrNotStarted
+	case <-ctx.Done():
+		p.pending.add(-1)
+		return ctx.Err()
+	}
+}
+
+// WaitIdle blocks until no submitted function is queued or running, or until ctx is done.
+//
+// Unlike stopping the executor and waiting for Done, the executor keeps accepting submissions.
+// Functions that are dropped because the executor was stopped before running them do not count
+// as queued. If ctx is done before the executor became idle, the context error is returned.
+func (p *LimitingExecutor) WaitIdle(ctx context.Context) error {
+	return p.pending.wait(ctx)
 }
 
 // Stop stops the executor. Goroutines that already were running will continue to run, unless cancelled otherwise.
@@ -97,8 +450,347 @@ func (p *LimitingExecutor) Stop() {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	if p.ingest != nil {
-		close(p.ingest)
-		p.ingest = nil
+	if p.quit != nil {
+		p.stop()
+	}
+}
+
+// stop stops the current run. The lock has to be held and the executor has to be started.
+func (p *LimitingExecutor) stop() {
+	close(p.quit)
+	p.ingest, p.quit = nil, nil
+}
+
+// Close stops the executor like Stop. It always returns nil and allows using the executor as io.Closer.
+func (p *LimitingExecutor) Close() error {
+	p.Stop()
+	return nil
+}
+
+// Done returns a channel that is closed once the executor stopped and all its running goroutines completed.
+//
+// It returns nil if the executor was never started.
+func (p *LimitingExecutor) Done() <-chan struct{} {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.stopped
+}
+
+type recoverExecutor struct {
+	inner   Executor
+	handler func(interface{})
+}
+
+// RecoverExecutor creates a new Executor that recovers panics of submitted functions.
+//
+// Every function submitted to the returned Executor is handed to inner, guarded by a deferred
+// recover that passes the recovered value to handler. Recovered panics are swallowed after
+// being handled; in particular, panics of functions run by Parallel and friends are not
+// converted to errors and their results are simply missing from the output.
+//
+// To also protect the bookkeeping of decorated executors like LimitingExecutor, RecoverExecutor
+// should be the outermost Executor.
+func RecoverExecutor(inner Executor, handler func(interface{})) Executor {
+	return recoverExecutor{inner, handler}
+}
+
+// Submit implements Executor.
+func (e recoverExecutor) Submit(f func()) {
+	e.inner.Submit(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				e.handler(r)
+			}
+		}()
+		f()
+	})
+}
+
+type labeledExecutor struct {
+	inner  Executor
+	labels pprof.LabelSet
+}
+
+// LabeledExecutor creates a new Executor that runs submitted functions with the given pprof labels.
+//
+// labels are key-value pairs as accepted by pprof.Labels, which panics for an odd number of them.
+// The labels are applied to the goroutine running a function for its duration, so that profiles
+// like the goroutine profile attribute it to the labels.
+func LabeledExecutor(inner Executor, labels ...string) Executor {
+	return labeledExecutor{inner, pprof.Labels(labels...)}
+}
+
+// Submit implements Executor.
+func (e labeledExecutor) Submit(f func()) {
+	e.inner.Submit(func() {
+		pprof.Do(context.Background(), e.labels, func(context.Context) {
+			f()
+		})
+	})
+}
+
+// InstrumentedExecutor is an Executor that counts the submissions to an inner Executor.
+type InstrumentedExecutor struct {
+	total       int64
+	inFlight    int64
+	maxInFlight int64
+
+	inner Executor
+}
+
+// InstrumentExecutor creates a new InstrumentedExecutor submitting to the given Executor.
+func InstrumentExecutor(inner Executor) *InstrumentedExecutor {
+	return &InstrumentedExecutor{inner: inner}
+}
+
+// Submit schedules f on the inner Executor, tracking it until it completes.
+func (e *InstrumentedExecutor) Submit(f func()) {
+	atomic.AddInt64(&e.total, 1)
+	inFlight := atomic.AddInt64(&e.inFlight, 1)
+	for {
+		max := atomic.LoadInt64(&e.maxInFlight)
+		if inFlight <= max || atomic.CompareAndSwapInt64(&e.maxInFlight, max, inFlight) {
+			break
+		}
 	}
+
+	e.inner.Submit(func() {
+		defer atomic.AddInt64(&e.inFlight, -1)
+		f()
+	})
+}
+
+// Snapshot returns the total number of submissions, the number of submitted functions that did not
+// complete yet and the maximum number of such functions observed at the same time.
+func (e *InstrumentedExecutor) Snapshot() (total, inFlight, maxInFlight int64) {
+	return atomic.LoadInt64(&e.total), atomic.LoadInt64(&e.inFlight), atomic.LoadInt64(&e.maxInFlight)
+}
+
+// DedupingExecutor is an Executor that drops keyed submissions whose key is already queued or running.
+type DedupingExecutor struct {
+	inner Executor
+
+	lock     sync.Mutex
+	inFlight map[string]struct{}
+}
+
+// DedupExecutor creates a new DedupingExecutor submitting to the given Executor.
+func DedupExecutor(inner Executor) *DedupingExecutor {
+	return &DedupingExecutor{inner: inner, inFlight: make(map[string]struct{})}
+}
+
+// Submit schedules f on the inner Executor without deduplication.
+func (e *DedupingExecutor) Submit(f func()) {
+	e.inner.Submit(f)
+}
+
+// SubmitKeyed schedules f on the inner Executor unless a function with the same key is already queued or running.
+//
+// Once the function of a key completed, the key is freed so later submissions with that key run again.
+func (e *DedupingExecutor) SubmitKeyed(key string, f func()) {
+	e.lock.Lock()
+	if _, ok := e.inFlight[key]; ok {
+		e.lock.Unlock()
+		return
+	}
+	e.inFlight[key] = struct{}{}
+	e.lock.Unlock()
+
+	e.inner.Submit(func() {
+		defer func() {
+			e.lock.Lock()
+			defer e.lock.Unlock()
+			delete(e.inFlight, key)
+		}()
+		f()
+	})
+}
+
+// DelayingExecutor is an Executor that can schedule functions to be submitted after a delay.
+type DelayingExecutor struct {
+	inner Executor
+}
+
+// DelayedExecutor creates a new DelayingExecutor submitting to the given Executor.
+func DelayedExecutor(inner Executor) *DelayingExecutor {
+	return &DelayingExecutor{inner: inner}
+}
+
+// Submit schedules f on the inner Executor without delay.
+func (e *DelayingExecutor) Submit(f func()) {
+	e.inner.Submit(f)
+}
+
+// SubmitAfter schedules f on the inner Executor once d elapsed.
+//
+// The returned function aborts the submission if it did not happen yet. It reports whether
+// the submission was aborted.
+func (e *DelayingExecutor) SubmitAfter(d time.Duration, f func()) (cancel func() bool) {
+	timer := time.AfterFunc(d, func() {
+		e.inner.Submit(f)
+	})
+	return timer.Stop
+}
+
+// RateLimitingExecutor is an Executor that submits at most a given number of functions per interval
+// to an underlying Executor.
+//
+// Submit blocks until submitting f does not exceed the rate. To cap both the rate and the number
+// of concurrently running functions, wrap a RateLimitingExecutor into a LimitingExecutor, e.g.
+// LimitExecutor(n, RateLimitExecutor(rate, per, UnlimitedExecutor)). The other way round, the
+// rate only applies to the submissions to the LimitingExecutor, which may start queued functions
+// in bursts once running functions complete.
+type RateLimitingExecutor struct {
+	rate     int
+	per      time.Duration
+	executor Executor
+
+	lock sync.Mutex
+	// submitted holds the times of the last rate submissions, oldest at next once it is full.
+	submitted []time.Time
+	next      int
+}
+
+// RateLimitExecutor creates a new Executor submitting at most rate functions per interval to the given Executor.
+//
+// It panics with an error wrapping ErrInvalidLimit if rate or per is not positive.
+func RateLimitExecutor(rate int, per time.Duration, executor Executor) *RateLimitingExecutor {
+	if rate <= 0 || per <= 0 {
+		panic(fmt.Errorf("%w: rate and interval have to be > 0 but were %d and %v", ErrInvalidLimit, rate, per))
+	}
+	return &RateLimitingExecutor{rate: rate, per: per, executor: executor}
+}
+
+// Submit schedules f on the underlying Executor, blocking until this does not exceed the rate.
+func (e *RateLimitingExecutor) Submit(f func()) {
+	_ = e.wait(context.Background())
+	e.executor.Submit(f)
+}
+
+// SubmitContext schedules f like Submit, giving up once ctx is done.
+//
+// If ctx is done before submitting f no longer exceeds the rate, f is not submitted and the
+// context error is returned. The slot reserved for f stays taken, so that giving up never lets
+// later submissions exceed the rate.
+func (e *RateLimitingExecutor) SubmitContext(ctx context.Context, f func()) error {
+	if err := e.wait(ctx); err != nil {
+		return err
+	}
+	e.executor.Submit(f)
+	return nil
+}
+
+// wait reserves the earliest slot that does not exceed the rate and blocks until it is due or ctx
+// is done. The lock is only held for the reservation, so that submitters wait concurrently.
+func (e *RateLimitingExecutor) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	at := time.Now()
+	if len(e.submitted) < e.rate {
+		e.submitted = append(e.submitted, at)
+	} else {
+		if due := e.submitted[e.next].Add(e.per); due.After(at) {
+			at = due
+		}
+		e.submitted[e.next] = at
+		e.next = (e.next + 1) % e.rate
+	}
+	e.lock.Unlock()
+
+	d := time.Until(at)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SerializingExecutor is an Executor that runs all submitted functions one after another on a single goroutine.
+//
+// Functions are run in the order they were submitted. Submissions never block, as the queue of
+// pending functions is unbounded.
+type SerializingExecutor struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	queue   []func()
+	running bool
+	stopped bool
+}
+
+// SerialExecutor creates a new SerializingExecutor and starts its goroutine.
+func SerialExecutor() *SerializingExecutor {
+	e := &SerializingExecutor{}
+	e.cond = sync.NewCond(&e.lock)
+	go e.work()
+	return e
+}
+
+// work runs the queued functions until the executor is stopped and the queue is empty.
+func (e *SerializingExecutor) work() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for {
+		for len(e.queue) == 0 && !e.stopped {
+			e.cond.Wait()
+		}
+		if len(e.queue) == 0 {
+			return
+		}
+
+		f := e.queue[0]
+		e.queue[0] = nil
+		e.queue = e.queue[1:]
+		e.running = true
+		e.lock.Unlock()
+
+		f()
+
+		e.lock.Lock()
+		e.running = false
+		e.cond.Broadcast()
+	}
+}
+
+// Submit queues f to be run after all previously submitted functions.
+//
+// It panics with ErrNotStarted if the executor is stopped.
+func (e *SerializingExecutor) Submit(f func()) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.stopped {
+		panic(ErrNotStarted)
+	}
+	e.queue = append(e.queue, f)
+	e.cond.Broadcast()
+}
+
+// Drain blocks until all submitted functions have been run.
+func (e *SerializingExecutor) Drain() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for len(e.queue) > 0 || e.running {
+		e.cond.Wait()
+	}
+}
+
+// Stop stops the executor from accepting submissions. Functions that were already submitted are still run.
+func (e *SerializingExecutor) Stop() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.stopped = true
+	e.cond.Broadcast()
 }
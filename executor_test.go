@@ -1,8 +1,12 @@
 package flow_test
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/adracus/flow"
 	"github.com/adracus/flow/mock"
@@ -50,5 +54,771 @@ var _ = Describe("Executor", func() {
 			ex.Submit(f2.Call)
 			ex.Submit(f3.Call)
 		})
+
+		It("should start the functions in the order of submission", func() {
+			var (
+				ex      = flow.LimitExecutor(3, flow.SyncExecutor)
+				started []int
+			)
+			ex.Start()
+
+			for i := 0; i < 100; i++ {
+				i := i
+				ex.Submit(func() { started = append(started, i) })
+			}
+			ex.Stop()
+			<-ex.Done()
+
+			Expect(started).To(HaveLen(100))
+			for i, n := range started {
+				Expect(n).To(Equal(i))
+			}
+		})
+	})
+
+	Describe("ExecutorFunc", func() {
+		It("should be usable as executor of a flow", func() {
+			var (
+				submissions int32
+				ex          = flow.ExecutorFunc(func(f func()) {
+					atomic.AddInt32(&submissions, 1)
+					go f()
+				})
+				f1 = mock.NewMockFunc(ctrl)
+				f2 = mock.NewMockFunc(ctrl)
+			)
+
+			f1.EXPECT().Call(gomock.Any())
+			f2.EXPECT().Call(gomock.Any())
+
+			Expect(flow.New(ex).Parallel(context.TODO(), f1.Call, f2.Call)).To(Succeed())
+			Expect(atomic.LoadInt32(&submissions)).To(Equal(int32(2)))
+		})
+	})
+
+	Describe("LimitingExecutor lifecycle", func() {
+		It("should stop the scheduler once the context passed to StartContext is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			ex := flow.LimitExecutor(1, flow.UnlimitedExecutor)
+			ex.StartContext(ctx)
+
+			ran := make(chan struct{})
+			ex.Submit(func() { close(ran) })
+			Eventually(ran).Should(BeClosed())
+
+			done := ex.Done()
+			Consistently(done).ShouldNot(BeClosed())
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+
+		It("should stop the scheduler on Close once running functions completed", func() {
+			var (
+				ex      = flow.LimitExecutor(1, flow.UnlimitedExecutor)
+				started = make(chan struct{})
+				release = make(chan struct{})
+			)
+			ex.Start()
+
+			ex.Submit(func() {
+				close(started)
+				<-release
+			})
+			Eventually(started).Should(BeClosed())
+
+			Expect(ex.Close()).To(Succeed())
+			Consistently(ex.Done()).ShouldNot(BeClosed())
+			close(release)
+			Eventually(ex.Done()).Should(BeClosed())
+		})
+	})
+
+	Describe("LimitingExecutor WaitIdle", func() {
+		It("should return once all submitted functions completed and keep accepting submissions", func() {
+			var (
+				ex      = flow.LimitExecutor(1, flow.UnlimitedExecutor)
+				release = make(chan struct{})
+				runs    int32
+				idle    = make(chan error, 1)
+			)
+			ex.Start()
+			defer ex.Stop()
+
+			for i := 0; i < 3; i++ {
+				ex.Submit(func() {
+					<-release
+					atomic.AddInt32(&runs, 1)
+				})
+			}
+			go func() { idle <- ex.WaitIdle(context.TODO()) }()
+
+			Consistently(idle, 50*time.Millisecond).ShouldNot(Receive())
+			close(release)
+			Eventually(idle).Should(Receive(BeNil()))
+			Expect(atomic.LoadInt32(&runs)).To(Equal(int32(3)))
+
+			ex.Submit(func() { atomic.AddInt32(&runs, 1) })
+			Expect(ex.WaitIdle(context.TODO())).To(Succeed())
+			Expect(atomic.LoadInt32(&runs)).To(Equal(int32(4)))
+		})
+
+		It("should return the context error if the executor does not become idle in time", func() {
+			var (
+				ex          = flow.LimitExecutor(1, flow.UnlimitedExecutor)
+				release     = make(chan struct{})
+				ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+			)
+			defer cancel()
+			ex.Start()
+			defer ex.Stop()
+			defer close(release)
+
+			ex.Submit(func() { <-release })
+			Expect(ex.WaitIdle(ctx)).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("should not wait for functions dropped by stopping the executor", func() {
+			var (
+				ex      = flow.LimitExecutor(1, flow.UnlimitedExecutor)
+				release = make(chan struct{})
+			)
+			ex.Start()
+
+			ex.Submit(func() { <-release })
+			ex.Submit(func() {})
+			ex.Stop()
+			close(release)
+
+			Expect(ex.WaitIdle(context.TODO())).To(Succeed())
+		})
+	})
+
+	Describe("WaitableExecutor", func() {
+		It("should signal the completion of functions submitted to a LimitingExecutor", func() {
+			var (
+				ex      = flow.LimitExecutor(1, flow.UnlimitedExecutor)
+				release = make(chan struct{})
+				ran     int32
+			)
+			ex.Start()
+			defer ex.Stop()
+
+			done := ex.SubmitWait(func() {
+				<-release
+				atomic.StoreInt32(&ran, 1)
+			})
+			Consistently(done, 20*time.Millisecond).ShouldNot(BeClosed())
+			close(release)
+			Eventually(done).Should(BeClosed())
+			Expect(atomic.LoadInt32(&ran)).To(Equal(int32(1)))
+		})
+
+		It("should signal the completion of functions submitted to a wrapped executor", func() {
+			var (
+				ex      = flow.WaitExecutor(flow.UnlimitedExecutor)
+				release = make(chan struct{})
+				ran     int32
+			)
+
+			done := ex.SubmitWait(func() {
+				<-release
+				atomic.StoreInt32(&ran, 1)
+			})
+			Consistently(done, 20*time.Millisecond).ShouldNot(BeClosed())
+			close(release)
+			Eventually(done).Should(BeClosed())
+			Expect(atomic.LoadInt32(&ran)).To(Equal(int32(1)))
+		})
+
+		It("should not wrap executors that are waitable already", func() {
+			ex := flow.LimitExecutor(1, flow.UnlimitedExecutor)
+			Expect(flow.WaitExecutor(ex)).To(BeIdenticalTo(ex))
+		})
+	})
+
+	Describe("LimitingExecutor restart", func() {
+		It("should accept submissions again once restarted after being stopped", func() {
+			ex := flow.LimitExecutor(1, flow.UnlimitedExecutor)
+
+			ex.Start()
+			ran := make(chan struct{})
+			ex.Submit(func() { close(ran) })
+			Eventually(ran).Should(BeClosed())
+			ex.Stop()
+			Eventually(ex.Done()).Should(BeClosed())
+
+			ex.Start()
+			defer ex.Stop()
+			ran = make(chan struct{})
+			ex.Submit(func() { close(ran) })
+			Eventually(ran).Should(BeClosed())
+			Expect(ex.Done()).NotTo(BeClosed())
+		})
+
+		It("should not let running functions of the previous run interfere", func() {
+			var (
+				ex      = flow.LimitExecutor(1, flow.UnlimitedExecutor)
+				release = make(chan struct{})
+				started = make(chan struct{})
+			)
+
+			ex.Start()
+			ex.Submit(func() {
+				close(started)
+				<-release
+			})
+			Eventually(started).Should(BeClosed())
+			ex.Stop()
+			first := ex.Done()
+
+			ex.Start()
+			defer ex.Stop()
+			Expect(flow.New(ex).Parallel(context.TODO(), func(context.Context) error { return nil })).To(Succeed())
+
+			Consistently(first).ShouldNot(BeClosed())
+			close(release)
+			Eventually(first).Should(BeClosed())
+			Expect(ex.Done()).NotTo(BeClosed())
+		})
+
+		It("should not panic with a closed channel when stopped during concurrent submissions", func() {
+			ex := flow.LimitExecutor(2, flow.UnlimitedExecutor)
+			ex.Start()
+
+			var wg sync.WaitGroup
+			wg.Add(10)
+			for i := 0; i < 10; i++ {
+				go func() {
+					defer wg.Done()
+					defer func() {
+						if r := recover(); r != nil {
+							Expect(r).To(BeIdenticalTo(flow.ErrNotStarted))
+						}
+					}()
+					ex.Submit(func() {})
+				}()
+			}
+			ex.Stop()
+			wg.Wait()
+		})
+	})
+
+	Describe("LimitingExecutor submission", func() {
+		It("should panic instead of blocking if the executor is not started", func(done Done) {
+			defer close(done)
+			var (
+				ex = flow.LimitExecutor(1, flow.UnlimitedExecutor)
+				f  = flow.New(ex)
+			)
+
+			defer func() {
+				Expect(recover()).To(BeIdenticalTo(flow.ErrNotStarted))
+			}()
+			_ = f.Parallel(context.TODO(), func(context.Context) error { return nil })
+		})
+
+		It("should panic if the executor was stopped", func() {
+			ex := flow.LimitExecutor(1, flow.UnlimitedExecutor)
+			ex.Start()
+			ex.Stop()
+
+			Expect(func() { ex.Submit(func() {}) }).To(Panic())
+		})
+	})
+
+	Describe("SubmitContext", func() {
+		It("should return the context error if the scheduler does not accept the function in time", func() {
+			var (
+				blocked = make(chan struct{})
+				release = make(chan struct{})
+				ex      = flow.LimitExecutor(1, flow.ExecutorFunc(func(f func()) {
+					close(blocked)
+					<-release
+					go f()
+				}))
+			)
+			ex.Start()
+			defer ex.Stop()
+			defer close(release)
+
+			Expect(ex.SubmitContext(context.TODO(), func() {})).To(Succeed())
+			Eventually(blocked).Should(BeClosed())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			Expect(ex.SubmitContext(ctx, func() {})).To(Equal(context.DeadlineExceeded))
+		})
+
+		It("should return ErrNotStarted if the executor is stopped", func() {
+			ex := flow.LimitExecutor(1, flow.UnlimitedExecutor)
+			ex.Start()
+			ex.Stop()
+
+			Expect(ex.SubmitContext(context.TODO(), func() {})).To(BeIdenticalTo(flow.ErrNotStarted))
+		})
+
+		It("should return ErrNotStarted without waiting for the deadline if the executor is stopped during shutdown", func() {
+			var (
+				blocked = make(chan struct{})
+				release = make(chan struct{})
+				ex      = flow.LimitExecutor(1, flow.ExecutorFunc(func(f func()) {
+					close(blocked)
+					<-release
+					go f()
+				}))
+			)
+			ex.Start()
+			defer close(release)
+
+			Expect(ex.SubmitContext(context.TODO(), func() {})).To(Succeed())
+			Eventually(blocked).Should(BeClosed())
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			var (
+				submitted = make(chan error, 1)
+				stopped   = make(chan struct{})
+			)
+			go func() { submitted <- ex.SubmitContext(ctx, func() {}) }()
+			go func() {
+				ex.Stop()
+				close(stopped)
+			}()
+
+			Eventually(stopped).Should(BeClosed())
+			Eventually(submitted).Should(Receive(BeIdenticalTo(flow.ErrNotStarted)))
+		})
+	})
+
+	Describe("Stop", func() {
+		It("should not wait for submissions blocked by the underlying executor", func() {
+			var (
+				blocked = make(chan struct{})
+				release = make(chan struct{})
+				ex      = flow.LimitExecutor(1, flow.ExecutorFunc(func(f func()) {
+					close(blocked)
+					<-release
+					go f()
+				}))
+			)
+			ex.Start()
+			defer close(release)
+
+			ex.Submit(func() {})
+			Eventually(blocked).Should(BeClosed())
+
+			submitted := make(chan struct{})
+			go func() {
+				defer close(submitted)
+				// Submit panics if Stop happens to run before it.
+				defer func() { recover() }()
+				ex.Submit(func() {})
+			}()
+
+			stopped := make(chan struct{})
+			go func() {
+				ex.Stop()
+				close(stopped)
+			}()
+			Eventually(stopped).Should(BeClosed())
+			Eventually(submitted).Should(BeClosed())
+		})
+	})
+
+	Describe("DelayingExecutor", func() {
+		It("should submit the function once the delay elapsed", func() {
+			var (
+				ex    = flow.DelayedExecutor(flow.UnlimitedExecutor)
+				ran   = make(chan time.Time, 1)
+				start = time.Now()
+			)
+
+			ex.SubmitAfter(20*time.Millisecond, func() { ran <- time.Now() })
+
+			var at time.Time
+			Eventually(ran).Should(Receive(&at))
+			Expect(at.Sub(start)).To(BeNumerically(">=", 20*time.Millisecond))
+		})
+
+		It("should not submit the function if cancelled before the delay elapsed", func() {
+			var (
+				mockEx = mock.NewMockExecutor(ctrl)
+				ex     = flow.DelayedExecutor(mockEx)
+			)
+
+			cancel := ex.SubmitAfter(20*time.Millisecond, func() {})
+			Expect(cancel()).To(BeTrue())
+			Consistently(func() bool { return cancel() }, 50*time.Millisecond).Should(BeFalse())
+		})
+	})
+
+	Describe("RateLimitingExecutor", func() {
+		It("should cap both the rate and the concurrency when wrapped into a LimitingExecutor", func() {
+			const (
+				rate = 5
+				per  = 100 * time.Millisecond
+			)
+			var (
+				lock      sync.Mutex
+				submitted []time.Time
+				inner     = flow.InstrumentExecutor(flow.ExecutorFunc(func(f func()) {
+					lock.Lock()
+					submitted = append(submitted, time.Now())
+					lock.Unlock()
+					go f()
+				}))
+				ex  = flow.LimitExecutor(2, flow.RateLimitExecutor(rate, per, inner))
+				fns = make([]flow.Func, 15)
+			)
+			ex.Start()
+			defer ex.Stop()
+
+			for i := range fns {
+				fns[i] = func(ctx context.Context) error {
+					time.Sleep(10 * time.Millisecond)
+					return nil
+				}
+			}
+			Expect(flow.New(ex).Parallel(context.TODO(), fns...)).To(Succeed())
+
+			total, _, maxInFlight := inner.Snapshot()
+			Expect(total).To(Equal(int64(len(fns))))
+			Expect(maxInFlight).To(BeNumerically("<=", 2))
+
+			lock.Lock()
+			defer lock.Unlock()
+			for i := rate; i < len(submitted); i++ {
+				// Allow for the delay between the rate limiter releasing a function and it being recorded.
+				Expect(submitted[i].Sub(submitted[i-rate])).To(BeNumerically(">=", per*9/10))
+			}
+		})
+
+		It("should let a cancelled submitter return while another one waits for its slot", func() {
+			var (
+				ex      = flow.RateLimitExecutor(1, time.Second, flow.UnlimitedExecutor)
+				waiting = make(chan struct{})
+			)
+			ex.Submit(func() {})
+			go func() {
+				defer close(waiting)
+				ex.Submit(func() {})
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			Expect(ex.SubmitContext(ctx, func() { Fail("submitted after cancellation") })).To(MatchError(context.DeadlineExceeded))
+			Expect(time.Since(start)).To(BeNumerically("<", 500*time.Millisecond))
+			Consistently(waiting, 50*time.Millisecond).ShouldNot(BeClosed())
+		})
+
+		It("should panic for a non-positive rate", func() {
+			Expect(func() { flow.RateLimitExecutor(0, time.Second, flow.UnlimitedExecutor) }).To(Panic())
+		})
+	})
+
+	Describe("SerializingExecutor", func() {
+		It("should run the functions in the order of submission", func() {
+			var (
+				ex    = flow.SerialExecutor()
+				order []int
+			)
+			defer ex.Stop()
+
+			for i := 0; i < 100; i++ {
+				i := i
+				ex.Submit(func() { order = append(order, i) })
+			}
+			ex.Drain()
+
+			Expect(order).To(HaveLen(100))
+			for i, n := range order {
+				Expect(n).To(Equal(i))
+			}
+		})
+
+		It("should run the already submitted functions but reject new ones once stopped", func() {
+			var (
+				ex   = flow.SerialExecutor()
+				runs int32
+			)
+
+			ex.Submit(func() { atomic.AddInt32(&runs, 1) })
+			ex.Stop()
+			ex.Drain()
+
+			Expect(atomic.LoadInt32(&runs)).To(Equal(int32(1)))
+			Expect(func() { ex.Submit(func() {}) }).To(Panic())
+		})
+	})
+
+	Describe("NewLimitExecutor", func() {
+		It("should reject a negative limit", func() {
+			_, err := flow.NewLimitExecutor(-1, flow.UnlimitedExecutor)
+			Expect(errors.Is(err, flow.ErrInvalidLimit)).To(BeTrue())
+		})
+
+		It("should accept a zero limit that never runs any function", func() {
+			ex, err := flow.NewLimitExecutor(0, flow.UnlimitedExecutor)
+			Expect(err).NotTo(HaveOccurred())
+			ex.Start()
+			defer ex.Stop()
+
+			ran := make(chan struct{})
+			ex.Submit(func() { close(ran) })
+			Consistently(ran).ShouldNot(BeClosed())
+		})
+
+		It("should create an executor running functions for a positive limit", func() {
+			ex, err := flow.NewLimitExecutor(1, flow.UnlimitedExecutor)
+			Expect(err).NotTo(HaveOccurred())
+			ex.Start()
+			defer ex.Stop()
+
+			ran := make(chan struct{})
+			ex.Submit(func() { close(ran) })
+			Eventually(ran).Should(BeClosed())
+		})
+	})
+
+	Describe("LimitExecutor", func() {
+		It("should panic on a negative limit", func() {
+			Expect(func() { flow.LimitExecutor(-1, flow.UnlimitedExecutor) }).To(Panic())
+		})
+	})
+
+	Describe("WithPrewarm", func() {
+		It("should submit the workers to the underlying executor on start", func() {
+			var (
+				submissions int32
+				ex          = flow.LimitExecutor(3, flow.ExecutorFunc(func(f func()) {
+					atomic.AddInt32(&submissions, 1)
+					go f()
+				}), flow.WithPrewarm())
+			)
+			ex.Start()
+			defer ex.Stop()
+
+			Eventually(func() int32 { return atomic.LoadInt32(&submissions) }).Should(Equal(int32(3)))
+			Expect(flow.New(ex).Parallel(context.TODO(),
+				func(context.Context) error { return nil },
+				func(context.Context) error { return nil },
+				func(context.Context) error { return nil },
+				func(context.Context) error { return nil },
+			)).To(Succeed())
+			Expect(atomic.LoadInt32(&submissions)).To(Equal(int32(3)))
+		})
+
+		It("should not run more functions than the limit at the same time", func() {
+			var (
+				ex      = flow.LimitExecutor(2, flow.UnlimitedExecutor, flow.WithPrewarm())
+				running int32
+				max     int32
+				fns     = make([]flow.Func, 10)
+			)
+			ex.Start()
+			defer ex.Stop()
+
+			for i := range fns {
+				fns[i] = func(context.Context) error {
+					n := atomic.AddInt32(&running, 1)
+					defer atomic.AddInt32(&running, -1)
+					for {
+						m := atomic.LoadInt32(&max)
+						if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+							break
+						}
+					}
+					time.Sleep(time.Millisecond)
+					return nil
+				}
+			}
+
+			Expect(flow.New(ex).Parallel(context.TODO(), fns...)).To(Succeed())
+			Expect(atomic.LoadInt32(&max)).To(BeNumerically("<=", 2))
+		})
+
+		It("should stop the workers once stopped", func() {
+			ex := flow.LimitExecutor(2, flow.UnlimitedExecutor, flow.WithPrewarm())
+			ex.Start()
+
+			ex.Stop()
+			Eventually(ex.Done()).Should(BeClosed())
+		})
+	})
+
+	Describe("RecoverExecutor", func() {
+		It("should pass the panic value to the handler and keep running", func() {
+			recovered := make(chan interface{}, 1)
+			ex := flow.RecoverExecutor(flow.UnlimitedExecutor, func(r interface{}) { recovered <- r })
+			f := flow.New(ex)
+
+			err := f.Parallel(context.TODO(),
+				func(ctx context.Context) error { panic("boom") },
+				func(ctx context.Context) error { return nil },
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(recovered).Should(Receive(Equal("boom")))
+		})
+	})
+
+	Describe("LabeledExecutor", func() {
+		It("should submit the functions to the inner executor", func() {
+			var (
+				mockEx = mock.NewMockExecutor(ctrl)
+				f      = mock.NewMockSubmitFunc(ctrl)
+				ex     = flow.LabeledExecutor(mockEx, "flow", "test")
+			)
+
+			mockEx.EXPECT().Submit(gomock.Any()).Do(func(f func()) { f() })
+			f.EXPECT().Call()
+
+			ex.Submit(f.Call)
+		})
+
+		It("should panic for an odd number of labels", func() {
+			Expect(func() { flow.LabeledExecutor(flow.UnlimitedExecutor, "flow") }).To(Panic())
+		})
+	})
+
+	Describe("ContextWithExecutor", func() {
+		It("should make the package-level functions use the executor of the context", func() {
+			var (
+				mockEx = mock.NewMockExecutor(ctrl)
+				f1     = mock.NewMockFunc(ctrl)
+				f2     = mock.NewMockFunc(ctrl)
+				ctx    = flow.ContextWithExecutor(context.TODO(), mockEx)
+			)
+
+			mockEx.EXPECT().Submit(gomock.Any()).Times(2).Do(func(f func()) { go f() })
+			f1.EXPECT().Call(ctx)
+			f2.EXPECT().Call(ctx)
+
+			Expect(flow.Parallel(ctx, f1.Call, f2.Call)).To(Succeed())
+		})
+
+		It("should not affect flows created with New", func() {
+			var (
+				mockEx = mock.NewMockExecutor(ctrl)
+				f1     = mock.NewMockFunc(ctrl)
+				ctx    = flow.ContextWithExecutor(context.TODO(), mockEx)
+			)
+
+			f1.EXPECT().Call(ctx)
+
+			Expect(flow.New(flow.UnlimitedExecutor).Parallel(ctx, f1.Call)).To(Succeed())
+		})
+	})
+
+	Describe("InstrumentedExecutor", func() {
+		snapshot := func(ex *flow.InstrumentedExecutor) []int64 {
+			total, inFlight, maxInFlight := ex.Snapshot()
+			return []int64{total, inFlight, maxInFlight}
+		}
+
+		It("should count total, in-flight and max in-flight submissions", func() {
+			var (
+				ex      = flow.InstrumentExecutor(flow.UnlimitedExecutor)
+				release = make(chan struct{})
+				wg      sync.WaitGroup
+			)
+
+			wg.Add(10)
+			for i := 0; i < 10; i++ {
+				go ex.Submit(func() {
+					defer wg.Done()
+					<-release
+				})
+			}
+
+			Eventually(func() []int64 { return snapshot(ex) }).Should(Equal([]int64{10, 10, 10}))
+			close(release)
+			wg.Wait()
+			Eventually(func() []int64 { return snapshot(ex) }).Should(Equal([]int64{10, 0, 10}))
+		})
+	})
+
+	Describe("DedupingExecutor", func() {
+		It("should run concurrent submissions of the same key only once", func() {
+			var (
+				ex      = flow.DedupExecutor(flow.UnlimitedExecutor)
+				calls   int32
+				started = make(chan struct{})
+				release = make(chan struct{})
+				wg      sync.WaitGroup
+			)
+
+			ex.SubmitKeyed("key", func() {
+				atomic.AddInt32(&calls, 1)
+				close(started)
+				<-release
+			})
+			Eventually(started).Should(BeClosed())
+
+			wg.Add(10)
+			for i := 0; i < 10; i++ {
+				go func() {
+					defer wg.Done()
+					ex.SubmitKeyed("key", func() { atomic.AddInt32(&calls, 1) })
+				}()
+			}
+			wg.Wait()
+			close(release)
+
+			Consistently(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(1)))
+		})
+
+		It("should run a key again once its function completed", func() {
+			var (
+				ex    = flow.DedupExecutor(flow.SyncExecutor)
+				calls int
+			)
+
+			ex.SubmitKeyed("key", func() { calls++ })
+			ex.SubmitKeyed("key", func() { calls++ })
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should not deduplicate submissions without a key", func() {
+			var (
+				ex    = flow.DedupExecutor(flow.SyncExecutor)
+				calls int
+			)
+
+			ex.Submit(func() { calls++ })
+			ex.Submit(func() { calls++ })
+			Expect(calls).To(Equal(2))
+		})
 	})
 })
+
+// benchmarkBurst measures the first burst of submissions to a freshly started executor, which is
+// where prewarming the workers pays off.
+func benchmarkBurst(b *testing.B, opts ...flow.LimitOption) {
+	var wg sync.WaitGroup
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		ex := flow.LimitExecutor(8, flow.UnlimitedExecutor, opts...)
+		ex.Start()
+		b.StartTimer()
+
+		wg.Add(64)
+		for j := 0; j < 64; j++ {
+			ex.Submit(wg.Done)
+		}
+		wg.Wait()
+
+		b.StopTimer()
+		ex.Stop()
+		b.StartTimer()
+	}
+}
+
+func BenchmarkLimitingExecutorBurst(b *testing.B) {
+	benchmarkBurst(b)
+}
+
+func BenchmarkLimitingExecutorBurstPrewarm(b *testing.B) {
+	benchmarkBurst(b, flow.WithPrewarm())
+}
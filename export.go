@@ -2,18 +2,128 @@ package flow
 
 var (
 	// Default returns the default *Flow with an UnlimitedExecutor.
-	Default = New(UnlimitedExecutor)
+	//
+	// If the context of an operation carries an Executor (see ContextWithExecutor), that Executor is
+	// used instead.
+	Default = &Flow{executor: UnlimitedExecutor, contextExecutor: true, inFlight: new(int64)}
 
 	// Parallel runs the given functions in parallel.
 	//
 	// It collects all the errors in the returned error. To obtain
 	// the multiple errors, use the `Errors` function.
 	Parallel = Default.Parallel
+	// ParallelBG runs the given functions in parallel with the background context.
+	//
+	// See Parallel for the semantics.
+	ParallelBG = Default.ParallelBG
+	// ParallelFunc returns a Func that runs the given functions in parallel when invoked.
+	//
+	// See Parallel for the semantics of the returned Func.
+	ParallelFunc = Default.ParallelFunc
+	// ParallelOn runs the given functions in parallel on the given executor instead of the one of the Flow.
+	//
+	// It collects all the errors in the returned error. To obtain
+	// the multiple errors, use the `Errors` function.
+	ParallelOn = Default.ParallelOn
 	// ParallelCancelOnError runs the given functions in parallel, cancelling all if one fails.
 	//
 	// It collects all the errors in the returned error. To obtain
 	// the multiple errors, use the `Errors` function.
 	ParallelCancelOnError = Default.ParallelCancelOnError
+	// ParallelUntilErrors runs the given functions in parallel, cancelling all once maxErrors of them failed.
+	//
+	// This tolerates up to maxErrors-1 failures; if maxErrors is not positive, it behaves like
+	// ParallelCancelOnError. Errors caused by the cancellation are marked as *CancellationError.
+	// It collects all the errors in the returned error. To obtain
+	// the multiple errors, use the `Errors` function.
+	ParallelUntilErrors = Default.ParallelUntilErrors
+	// ParallelFailFast runs the given functions in parallel, returning the first error as soon as it occurs.
+	//
+	// The remaining functions are neither cancelled nor waited for but continue running detached
+	// from the caller. If all functions succeed, nil is returned once all of them completed.
+	ParallelFailFast = Default.ParallelFailFast
+	// ParallelGraceful runs the given functions in parallel, giving them a grace period to complete once the context is done.
+	//
+	// The functions run with ctx, so its cancellation signals them to stop. Once ctx is done, the
+	// functions are given grace to complete, e.g. to flush their state, before ParallelGraceful
+	// returns. Functions that did not complete by then are abandoned; they continue running detached
+	// from the caller and the error of ctx is collected in place of each of them. It collects all the
+	// errors in the returned error. To obtain the multiple errors, use the `Errors` function.
+	ParallelGraceful = Default.ParallelGraceful
+	// ParallelTry runs the given functions in parallel, waiting at most d for them to complete.
+	//
+	// It returns the number of functions that completed within d together with the errors of those that
+	// failed. Functions that did not complete in time are abandoned: they are neither cancelled nor
+	// waited for but continue running detached from the caller. It collects all the errors in the
+	// returned error. To obtain the multiple errors, use the `Errors` function.
+	ParallelTry = Default.ParallelTry
+	// ParallelTimeouts runs the given functions in parallel, each with its own timeout.
+	//
+	// The i-th function runs with a context that expires after timeouts[i]; a zero timeout means the
+	// function only observes the context passed to ParallelTimeouts. If the number of timeouts differs
+	// from the number of functions, an error wrapping ErrLengthMismatch is returned without running
+	// any of them. It collects all the errors in the returned error. To obtain
+	// the multiple errors, use the `Errors` function.
+	ParallelTimeouts = Default.ParallelTimeouts
+	// ParallelTimed runs the given functions in parallel, returning the error and duration of each of them.
+	//
+	// The i-th result describes the execution of the i-th function.
+	ParallelTimed = Default.ParallelTimed
+	// ParallelWithCancel runs the given functions in parallel, passing each of them the cancel function of the group.
+	//
+	// Any function may cancel all functions of the group by calling the cancel function, regardless of
+	// whether it fails or not. It collects all the errors in the returned error. To obtain
+	// the multiple errors, use the `Errors` function.
+	ParallelWithCancel = Default.ParallelWithCancel
+	// ParallelHandle starts running the given functions in parallel and returns without waiting for them.
+	//
+	// Calling wait blocks until all functions completed and returns the collected errors. To obtain
+	// the multiple errors, use the `Errors` function. Calling cancel cancels the context of all
+	// functions, e.g. from another goroutine; the resulting cancellations are reported as
+	// CancellationError. Both may be called any number of times.
+	ParallelHandle = Default.ParallelHandle
+	// Stages runs the given stages one after another, running the functions of each stage in parallel.
+	//
+	// The next stage is only started if all functions of the prior stage succeeded. Otherwise, the
+	// collected errors of the failing stage are returned. Empty stages are skipped.
+	Stages = Default.Stages
+	// ParallelGroups runs the functions of all given groups in parallel as one batch.
+	//
+	// If any function fails, a *GroupError is returned that holds the errors by the index of the group
+	// of the failed function. The error aggregator of the Flow is not applied.
+	ParallelGroups = Default.ParallelGroups
+	// NamedGroups runs the functions of all given named groups in parallel as one batch, cancelling all once one fails.
+	//
+	// The first failure cancels the context of all functions with a cause identifying the group of the
+	// failed function, like `group "name" failed: <error>`, which the functions can obtain using
+	// context.Cause. Once all functions completed, that cause is returned; it wraps the error of the
+	// failed function.
+	NamedGroups = Default.NamedGroups
+	// ParallelNamed runs the given named functions in parallel and returns the errors of the failed
+	// functions by their names.
+	//
+	// The names of the succeeded functions are omitted, so that the returned map is empty if all
+	// functions succeeded. If the Flow rejects the execution, e.g. because its maximum fan-out is
+	// exceeded, none of the functions is run and each is reported with that error.
+	ParallelNamed = Default.ParallelNamed
+	// NewScope creates a new Scope whose functions are run with the given context.
+	NewScope = Default.NewScope
+	// NewNursery creates a new Nursery whose functions are run with a context derived from ctx.
+	//
+	// The derived context is cancelled the first time a function of the Nursery fails or once Wait
+	// returns, whichever occurs first.
+	NewNursery = Default.NewNursery
+	// SequenceBudget runs the given computations one after another, splitting the total time budget across them.
+	//
+	// Every function runs with a context that expires after an equal share of the budget that remains
+	// for it and the functions after it, so time left over by a function rolls into the shares of the
+	// later ones. Apart from that, it behaves like Sequence.
+	SequenceBudget = Default.SequenceBudget
+	// SequenceRecover runs the given computations one after another, converting panics into errors.
+	//
+	// A panic in the i-th function stops the sequence like an error would. The returned error wraps
+	// a *PanicError holding the panic value and the stack trace. Apart from that, it behaves like Sequence.
+	SequenceRecover = Default.SequenceRecover
 	// Race runs all functions in parallel and returns the first that completes.
 	//
 	// Completion means a function either errors or succeeds.
@@ -28,32 +138,98 @@ var (
 	ParallelString = Default.ParallelString
 	// ParallelStringCancelOnError runs the given functions in parallel, cancelling all if one fails.
 	//
+	// Results are processed in the order they are received. The returned results are exactly the
+	// successful results received before the first error; later results are discarded, but all
+	// errors are collected. To obtain the multiple errors, use the `Errors` function.
+	ParallelStringCancelOnError = Default.ParallelStringCancelOnError
+	// ParallelStringIndexed runs the given functions in parallel, returning their results and errors by index.
+	//
+	// The i-th result holds the result of the i-th function, or the empty string if it failed.
+	// The errors of the failed functions are returned by their index; if no function failed, the
+	// returned map is nil.
+	ParallelStringIndexed = Default.ParallelStringIndexed
+	// ParallelNamedString runs the given named functions in parallel, returning their results and errors by name.
+	//
+	// The results of the succeeded functions and the errors of the failed functions are returned in
+	// separate maps; if no function failed, the returned error map is nil.
+	ParallelNamedString = Default.ParallelNamedString
+	// ParallelStringWithDeadline runs the given functions in parallel, collecting the results that arrive within d.
+	//
+	// Once d elapsed, the remaining functions are cancelled and the results collected so far are returned
+	// along with ErrPartialDeadline. The same applies if the context expires before. Results that were
+	// already sent when the deadline is reached are still collected.
 	// It collects all the errors in the returned error. To obtain
 	// the multiple errors, use the `Errors` function.
-	ParallelStringCancelOnError = Default.ParallelStringCancelOnError
+	ParallelStringWithDeadline = Default.ParallelStringWithDeadline
 	// RaceString runs all functions in parallel and returns the first that completes.
 	//
 	// Completion means a function either errors or succeeds.
 	// The result of the succeeded function is returned, the other results are
 	// discarded.
 	RaceString = Default.RaceString
+	// ParallelStringStream runs the given functions in parallel, streaming their results as they complete.
+	//
+	// With Block, functions whose results cannot be delivered wait for the consumer or until the
+	// context is done, in which case their results are discarded. With DropOldest, the oldest buffered
+	// result is discarded in favor of the new one; use Dropped to obtain the number of discarded results.
+	// If the Flow rejects the execution, e.g. because its maximum fan-out is exceeded, none of the
+	// functions is run and the stream delivers a single result with index -1 holding that error.
+	ParallelStringStream = Default.ParallelStringStream
+	// FirstString runs all functions in parallel and returns the result of the first that succeeds.
+	//
+	// Once a function succeeded, the remaining ones are cancelled and their results are discarded.
+	// If all functions fail, their errors are collected in the returned error. To obtain
+	// the multiple errors, use the `Errors` function.
+	FirstString = Default.FirstString
+	// RaceStringSuccess runs all functions in parallel and returns the result of the first that succeeds.
+	//
+	// Unlike RaceString, failed completions do not end the race. Once a function succeeded, the
+	// remaining ones are cancelled and their results are discarded. If all functions fail, the error
+	// of the function that failed last is returned.
+	RaceStringSuccess = Default.RaceStringSuccess
+	// QuorumString runs all functions in parallel and returns the results of the first k that succeed.
+	//
+	// Once k functions succeeded, the remaining ones are cancelled and their results are discarded.
+	// If so many functions fail that k successes are no longer possible, the remaining functions are
+	// cancelled as well and the errors are returned alongside the results obtained so far.
+	QuorumString = Default.QuorumString
 
 	// ParallelInt runs the given functions in parallel.
 	//
 	// It collects all the errors in the returned error. To obtain
 	// the multiple errors, use the `Errors` function.
 	ParallelInt = Default.ParallelInt
-	// ParallelIntCancelOnError runs the given functions in parallel, cancelling all if one fails.
+	// ParallelIntInto runs the given functions in parallel, sending each successful result to out as it completes.
 	//
+	// out is not closed, as it is owned by the caller. The caller has to drain out concurrently:
+	// if out is unbuffered or full and not drained, ParallelIntInto blocks forever.
 	// It collects all the errors in the returned error. To obtain
 	// the multiple errors, use the `Errors` function.
+	ParallelIntInto = Default.ParallelIntInto
+	// ParallelIntCancelOnError runs the given functions in parallel, cancelling all if one fails.
+	//
+	// Results are processed in the order they are received. The returned results are exactly the
+	// successful results received before the first error; later results are discarded, but all
+	// errors are collected. To obtain the multiple errors, use the `Errors` function.
 	ParallelIntCancelOnError = Default.ParallelIntCancelOnError
+	// ParallelIntCancelOnErrorIndexed runs the given functions in parallel, cancelling all if one fails.
+	//
+	// The results of the functions that succeeded are returned by their index. Unlike
+	// ParallelIntCancelOnError, errors caused by the cancellation are not collected, so the returned
+	// error only holds the genuine failures. To obtain the multiple errors, use the `Errors` function.
+	ParallelIntCancelOnErrorIndexed = Default.ParallelIntCancelOnErrorIndexed
 	// RaceInt runs all functions in parallel and returns the first that completes.
 	//
 	// Completion means a function either errors or succeeds.
 	// The result of the succeeded function is returned, the other results are
 	// discarded.
 	RaceInt = Default.RaceInt
+	// RaceIntIndexed runs all functions in parallel and returns the results and the index of the first that completes.
+	//
+	// Completion means a function either errors or succeeds.
+	// The result of the succeeded function is returned, the other results are
+	// discarded. If no function completed, the returned index is -1.
+	RaceIntIndexed = Default.RaceIntIndexed
 
 	// ParallelBool runs the given functions in parallel.
 	//
@@ -62,8 +238,9 @@ var (
 	ParallelBool = Default.ParallelBool
 	// ParallelBoolCancelOnError runs the given functions in parallel, cancelling all if one fails.
 	//
-	// It collects all the errors in the returned error. To obtain
-	// the multiple errors, use the `Errors` function.
+	// Results are processed in the order they are received. The returned results are exactly the
+	// successful results received before the first error; later results are discarded, but all
+	// errors are collected. To obtain the multiple errors, use the `Errors` function.
 	ParallelBoolCancelOnError = Default.ParallelBoolCancelOnError
 	// RaceBool runs all functions in parallel and returns the first that completes.
 	//
@@ -72,7 +249,44 @@ var (
 	// discarded.
 	RaceBool = Default.RaceBool
 
+	// RaceCloser runs all functions in parallel and returns the results of the first that completes.
+	//
+	// Completion means a function either errors or succeeds.
+	// The result of the succeeded function is returned, the other results are
+	// discarded. Non-nil io.Closer results of the other functions are closed.
+	RaceCloser = Default.RaceCloser
+	// RaceAny runs all functions in parallel and returns the first that completes.
+	//
+	// Completion means a function either errors or succeeds.
+	// The result of the succeeded function is returned, the other results are
+	// discarded. Unlike the typed variants, the functions may return results of different types.
+	RaceAny = Default.RaceAny
+
 	// RaceCond runs all functions in parallel and returns the result of the first function that completes with an
 	// error or with a truthy result.
+	//
+	// Results are considered in the order they are received, so if multiple functions qualify, the one
+	// that completed first wins.
 	RaceCond = Default.RaceCond
+	// WaitForAny polls all checks in parallel every interval and returns the index of the first that reports true.
+	//
+	// Each check is polled independently until it reports true or the context expires; failed polls
+	// count as false. Once a check reported true, the polling of the others is cancelled. If the
+	// context expires before, -1 is returned together with the context error.
+	WaitForAny = Default.WaitForAny
+	// VoteBool runs all functions in parallel and returns true once at least threshold of them returned true.
+	//
+	// Once the threshold is reached, or too many functions returned false or failed to still reach it,
+	// the remaining functions are cancelled and their results are discarded. In the latter case false
+	// is returned together with the errors that occurred so far. If fewer than threshold functions
+	// are given, ErrQuorumUnreachable is returned without running any of them.
+	VoteBool = Default.VoteBool
+	// SearchBool runs the functions in order with at most concurrency of them running at the same time,
+	// returning the result of the first function that completes with an error or with a truthy result.
+	//
+	// Unlike RaceCond, functions are launched one after another as the previous ones complete. Once a
+	// qualifying result is received, no further functions are launched and the running ones are cancelled.
+	// If the context expires before a result qualified, the search is aborted and the context error is
+	// returned.
+	SearchBool = Default.SearchBool
 )
@@ -0,0 +1,66 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrFanoutExceeded is returned if more functions are passed to a Flow than its maximum fan-out allows.
+var ErrFanoutExceeded = errors.New("fan-out exceeded")
+
+// WithMaxFanout makes the Flow reject executions of more than n functions.
+//
+// Unlike limiting the concurrency with a LimitingExecutor, which queues the functions, an execution
+// exceeding the maximum fan-out fails with ErrFanoutExceeded before any of its functions is run.
+// If n is not positive, the fan-out is not limited.
+func WithMaxFanout(n int) Option {
+	return func(f *Flow) {
+		f.maxFanout = n
+	}
+}
+
+// check returns an error if an execution of l functions with ctx violates the maximum fan-out or
+// the deadline requirement of the Flow.
+func (f *Flow) check(ctx context.Context, l int) error {
+	if err := f.checkFanout(l); err != nil {
+		return err
+	}
+	return f.checkDeadline(ctx)
+}
+
+// checkFanout returns an error wrapping ErrFanoutExceeded if l exceeds the maximum fan-out of the Flow.
+func (f *Flow) checkFanout(l int) error {
+	if f.maxFanout > 0 && l > f.maxFanout {
+		return fmt.Errorf("%w: %d functions exceed the maximum of %d", ErrFanoutExceeded, l, f.maxFanout)
+	}
+	return nil
+}
+
+// ErrNoDeadline is returned by the parallel executions of a Flow created with WithRequireDeadline
+// if they are called with a context that has no deadline.
+var ErrNoDeadline = errors.New("context has no deadline")
+
+// WithRequireDeadline makes the parallel executions of the Flow reject contexts without a deadline.
+//
+// This enforces that every fan-out is bounded in time, e.g. to catch a context.Background that was
+// passed by accident. It applies to every execution that also respects the maximum fan-out (see
+// WithMaxFanout), including the typed variants, the Race variants, MapN and DAG.Run. A rejected
+// execution fails with ErrNoDeadline before any of its functions is run. Executions without
+// functions are not checked.
+func WithRequireDeadline() Option {
+	return func(f *Flow) {
+		f.requireDeadline = true
+	}
+}
+
+// checkDeadline returns ErrNoDeadline if the Flow requires a deadline and ctx has none.
+func (f *Flow) checkDeadline(ctx context.Context) error {
+	if !f.requireDeadline {
+		return nil
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return ErrNoDeadline
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package flow_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fanout", func() {
+	Describe("WithMaxFanout", func() {
+		var (
+			calls int32
+			fns   []Func
+			f     *Flow
+		)
+		BeforeEach(func() {
+			calls = 0
+			fns = make([]Func, 3)
+			for i := range fns {
+				fns[i] = func(context.Context) error {
+					atomic.AddInt32(&calls, 1)
+					return nil
+				}
+			}
+			f = New(UnlimitedExecutor, WithMaxFanout(3))
+		})
+
+		It("should run the functions if their number does not exceed the maximum", func() {
+			Expect(f.Parallel(context.TODO(), fns...)).To(Succeed())
+			Expect(f.Race(context.TODO(), fns[:2]...)).To(Succeed())
+			Expect(atomic.LoadInt32(&calls)).To(BeNumerically(">=", 4))
+		})
+
+		It("should reject the execution without running any function if the maximum is exceeded", func() {
+			fns = append(fns, fns[0])
+
+			err := f.Parallel(context.TODO(), fns...)
+			Expect(errors.Is(err, ErrFanoutExceeded)).To(BeTrue())
+
+			err = f.Race(context.TODO(), fns...)
+			Expect(errors.Is(err, ErrFanoutExceeded)).To(BeTrue())
+
+			_, err = f.ParallelString(context.TODO(), make([]StringFunc, 4)...)
+			Expect(errors.Is(err, ErrFanoutExceeded)).To(BeTrue())
+
+			for _, res := range f.ParallelTimed(context.TODO(), fns...) {
+				Expect(errors.Is(res.Err, ErrFanoutExceeded)).To(BeTrue())
+			}
+
+			var results []StringResult
+			for res := range f.ParallelStringStream(context.TODO(), make([]StringFunc, 4)).Results() {
+				results = append(results, res)
+			}
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Index).To(Equal(-1))
+			Expect(errors.Is(results[0].Err, ErrFanoutExceeded)).To(BeTrue())
+			Expect(atomic.LoadInt32(&calls)).To(BeZero())
+		})
+	})
+
+	Describe("WithRequireDeadline", func() {
+		var (
+			calls int32
+			fn    Func
+			f     *Flow
+		)
+		BeforeEach(func() {
+			calls = 0
+			fn = func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			}
+			f = New(UnlimitedExecutor, WithRequireDeadline())
+		})
+
+		It("should reject contexts without a deadline without running any function", func() {
+			Expect(f.Parallel(context.Background(), fn, fn)).To(BeIdenticalTo(ErrNoDeadline))
+			Expect(f.Race(context.Background(), fn, fn)).To(BeIdenticalTo(ErrNoDeadline))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			Expect(f.Parallel(ctx, fn)).To(BeIdenticalTo(ErrNoDeadline))
+			Expect(atomic.LoadInt32(&calls)).To(BeZero())
+		})
+
+		It("should reject contexts without a deadline in the other executions", func() {
+			Expect(f.ParallelCancelOnError(context.Background(), fn, fn)).To(BeIdenticalTo(ErrNoDeadline))
+
+			str := func(context.Context) (string, error) {
+				atomic.AddInt32(&calls, 1)
+				return "", nil
+			}
+			_, err := f.ParallelString(context.Background(), str)
+			Expect(err).To(BeIdenticalTo(ErrNoDeadline))
+			_, err = f.RaceString(context.Background(), str, str)
+			Expect(err).To(BeIdenticalTo(ErrNoDeadline))
+
+			_, err = MapN(context.Background(), f, 1, []int{1, 2}, func(context.Context, int) (int, error) {
+				atomic.AddInt32(&calls, 1)
+				return 0, nil
+			})
+			Expect(err).To(BeIdenticalTo(ErrNoDeadline))
+			Expect(atomic.LoadInt32(&calls)).To(BeZero())
+		})
+
+		It("should accept contexts with a deadline", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			Expect(f.Parallel(ctx, fn, fn)).To(Succeed())
+			Expect(f.Race(ctx, fn)).To(Succeed())
+			Expect(atomic.LoadInt32(&calls)).To(BeNumerically("==", 3))
+		})
+
+		It("should not require a deadline by default", func() {
+			Expect(New(UnlimitedExecutor).Parallel(context.Background(), fn)).To(Succeed())
+		})
+	})
+})
@@ -3,13 +3,25 @@
 // The core assumption is that all functions suffice the `Func` type
 // definition. Currently, if you ever require to handle moving values
 // between the functions, you should make use of proper 'closurization'.
+//
+// Nil functions passed to the parallel operations (Parallel, Race and their
+// variants) are skipped, whereas Sequence rejects them with ErrNilFunc.
 package flow
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
 // Func is a context-aware computation that may produce an error.
@@ -24,6 +36,155 @@ type IntFunc func(context.Context) (int, error)
 // BoolFunc is a context-aware computation that may produce an error or a bool.
 type BoolFunc func(context.Context) (bool, error)
 
+// Wrap adapts fn to a Func that ignores its context.
+func Wrap(fn func() error) Func {
+	return func(context.Context) error {
+		return fn()
+	}
+}
+
+// WrapString adapts fn to a StringFunc that ignores its context.
+func WrapString(fn func() (string, error)) StringFunc {
+	return func(context.Context) (string, error) {
+		return fn()
+	}
+}
+
+// WrapInt adapts fn to an IntFunc that ignores its context.
+func WrapInt(fn func() (int, error)) IntFunc {
+	return func(context.Context) (int, error) {
+		return fn()
+	}
+}
+
+// WrapBool adapts fn to a BoolFunc that ignores its context.
+func WrapBool(fn func() (bool, error)) BoolFunc {
+	return func(context.Context) (bool, error) {
+		return fn()
+	}
+}
+
+// Discard adapts fn to a Func that discards the result of fn.
+func (fn StringFunc) Discard() Func {
+	return func(ctx context.Context) error {
+		_, err := fn(ctx)
+		return err
+	}
+}
+
+// Map returns an IntFunc whose result is the result of fn transformed by m.
+//
+// If fn fails, m is not called and the error is returned with a zero result.
+func (fn IntFunc) Map(m func(int) int) IntFunc {
+	return func(ctx context.Context) (int, error) {
+		n, err := fn(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return m(n), nil
+	}
+}
+
+// ToBool returns a BoolFunc whose result is the result of fn converted by pred.
+//
+// If fn fails, pred is not called and the error is returned with a false result.
+func (fn IntFunc) ToBool(pred func(int) bool) BoolFunc {
+	return func(ctx context.Context) (bool, error) {
+		n, err := fn(ctx)
+		if err != nil {
+			return false, err
+		}
+		return pred(n), nil
+	}
+}
+
+// Negate returns a BoolFunc whose result is the negated result of fn.
+//
+// If fn fails, the error is returned with a false result.
+func (fn BoolFunc) Negate() BoolFunc {
+	return func(ctx context.Context) (bool, error) {
+		ok, err := fn(ctx)
+		if err != nil {
+			return false, err
+		}
+		return !ok, nil
+	}
+}
+
+// ErrNilFunc is returned by Sequence if one of the given functions is nil.
+var ErrNilFunc = errors.New("nil function")
+
+// indexedError is an error that occurred at a specific index of a parallel execution.
+type indexedError struct {
+	index int
+	err   error
+}
+
+// withIndex returns err as indexedError, or nil if err is nil.
+func withIndex(index int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return indexedError{index, err}
+}
+
+// Error implements error.
+func (e indexedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e indexedError) Unwrap() error {
+	return e.err
+}
+
+// CancellationError marks an error that resulted from the flow cancelling a function.
+//
+// Operations that cancel their remaining functions once one of them fails (or once a function
+// requests it) wrap the context.Canceled errors caused by that cancellation into a CancellationError,
+// separating them from genuine failures. Use errors.As to detect them.
+type CancellationError struct {
+	Err error
+}
+
+// Error implements error.
+func (e *CancellationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *CancellationError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is the error a recovered panic is converted to.
+type PanicError struct {
+	// Value is the value the function panicked with.
+	Value interface{}
+	// Stack is the stack trace of the panicking goroutine at the time of the recovery.
+	Stack []byte
+}
+
+// Error implements error.
+func (e *PanicError) Error() string {
+	return fmt.Sprint(e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// markCancellation wraps err into a CancellationError if it is a cancellation of ctx caused by the
+// flow itself, i.e. while parent, the context ctx was derived from, is still active.
+func markCancellation(parent, ctx context.Context, err error) error {
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil && parent.Err() == nil {
+		return &CancellationError{err}
+	}
+	return err
+}
+
 type multiError []error
 
 // Error implements error.
@@ -51,10 +212,112 @@ func (m multiError) ErrorOrNil() error {
 
 // Errors retrieves all causes of a parallel execution.
 func Errors(err error) []error {
-	if m, ok := err.(multiError); ok {
-		return m
+	m, ok := err.(multiError)
+	if !ok {
+		return nil
 	}
-	return nil
+
+	out := make([]error, len(m))
+	for i, err := range m {
+		if ierr, ok := err.(indexedError); ok {
+			err = ierr.err
+		}
+		out[i] = err
+	}
+	return out
+}
+
+// AllErrors combines all errors into one. To obtain the multiple errors, use the `Errors` function.
+//
+// This is the default aggregation of a Flow.
+func AllErrors(errs []error) error {
+	return multiError(errs).ErrorOrNil()
+}
+
+// FirstError returns the first of the given errors.
+func FirstError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
+}
+
+// LastError returns the last of the given errors.
+func LastError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[len(errs)-1]
+}
+
+// ErrorsByIndex retrieves the causes of a parallel execution by the index of the function that failed.
+func ErrorsByIndex(err error) map[int]error {
+	m, ok := err.(multiError)
+	if !ok {
+		return nil
+	}
+
+	out := make(map[int]error, len(m))
+	for _, err := range m {
+		if ierr, ok := err.(indexedError); ok {
+			out[ierr.index] = ierr.err
+		}
+	}
+	return out
+}
+
+// duplicateError is an error that occurred count times in a parallel execution.
+type duplicateError struct {
+	err   error
+	count int
+}
+
+// Error implements error.
+func (e duplicateError) Error() string {
+	return fmt.Sprintf("%s (x%d)", e.err.Error(), e.count)
+}
+
+// Unwrap returns the underlying error.
+func (e duplicateError) Unwrap() error {
+	return e.err
+}
+
+// Dedup collapses the causes of a parallel execution with identical messages into one.
+//
+// The first cause of every distinct message is kept, in order, with the number of occurrences
+// appended to its message if it occurred more than once. The kept cause can still be
+// inspected with errors.Is and errors.As. If err is not the result of a parallel
+// execution, it is returned as is.
+func Dedup(err error) error {
+	m, ok := err.(multiError)
+	if !ok {
+		return err
+	}
+
+	var (
+		out     multiError
+		counts  = make(map[string]int, len(m))
+		indices = make(map[string]int, len(m))
+	)
+	for _, err := range m {
+		msg := err.Error()
+		if counts[msg] == 0 {
+			indices[msg] = len(out)
+			out = append(out, err)
+		}
+		counts[msg]++
+	}
+	for msg, i := range indices {
+		if counts[msg] == 1 {
+			continue
+		}
+		if ierr, ok := out[i].(indexedError); ok {
+			out[i] = indexedError{ierr.index, duplicateError{ierr.err, counts[msg]}}
+			continue
+		}
+		out[i] = duplicateError{out[i], counts[msg]}
+	}
+	return out
 }
 
 // Sequence runs the given computations one after another.
@@ -62,9 +325,81 @@ func Errors(err error) []error {
 // If one of the functions fails, the sequence stops immediately and the error
 // is returned.
 // If the context expires between the functions, the context error is returned.
+// If any of the functions is nil, ErrNilFunc is returned before running any of them.
 func Sequence(ctx context.Context, fns ...Func) error {
+	return Default.Sequence(ctx, fns...)
+}
+
+// SequenceFunc returns a Func that runs the given computations one after another when invoked.
+//
+// See Sequence for the semantics of the returned Func.
+func SequenceFunc(fns ...Func) Func {
+	return Default.SequenceFunc(fns...)
+}
+
+// Sequence runs the given computations one after another like the package-level Sequence, running
+// every function in a span if f has a tracer.
+func (f *Flow) Sequence(ctx context.Context, fns ...Func) error {
+	for _, fn := range fns {
+		if fn == nil {
+			return ErrNilFunc
+		}
+	}
+
+	for i, fn := range fns {
+		if err := f.trace(ctx, "sequence", i, fn); err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SequenceRecover runs the given computations one after another, converting panics into errors.
+//
+// A panic in the i-th function stops the sequence like an error would. The returned error wraps
+// a *PanicError holding the panic value and the stack trace. Apart from that, it behaves like Sequence.
+func (f *Flow) SequenceRecover(ctx context.Context, fns ...Func) error {
+	recovering := make([]Func, len(fns))
+	for i, fn := range fns {
+		if fn == nil {
+			return ErrNilFunc
+		}
+
+		i, fn := i, fn
+		recovering[i] = func(ctx context.Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic in step %d: %w", i, &PanicError{Value: r, Stack: debug.Stack()})
+				}
+			}()
+			return fn(ctx)
+		}
+	}
+	return f.Sequence(ctx, recovering...)
+}
+
+// SequenceBudget runs the given computations one after another, splitting the total time budget across them.
+//
+// Every function runs with a context that expires after an equal share of the budget that remains
+// for it and the functions after it, so time left over by a function rolls into the shares of the
+// later ones. Apart from that, it behaves like Sequence.
+func (f *Flow) SequenceBudget(ctx context.Context, total time.Duration, fns ...Func) error {
 	for _, fn := range fns {
-		if err := fn(ctx); err != nil {
+		if fn == nil {
+			return ErrNilFunc
+		}
+	}
+
+	deadline := time.Now().Add(total)
+	for i, fn := range fns {
+		stepCtx, cancel := context.WithTimeout(ctx, time.Until(deadline)/time.Duration(len(fns)-i))
+		err := f.trace(stepCtx, "sequence", i, fn)
+		cancel()
+		if err != nil {
 			return err
 		}
 
@@ -75,415 +410,2243 @@ func Sequence(ctx context.Context, fns ...Func) error {
 	return nil
 }
 
+// SequenceFunc returns a Func that runs the given computations one after another with f when invoked.
+//
+// See Flow.Sequence for the semantics of the returned Func.
+func (f *Flow) SequenceFunc(fns ...Func) Func {
+	return func(ctx context.Context) error {
+		return f.Sequence(ctx, fns...)
+	}
+}
+
+// CoalesceString runs the given computations one after another, returning the first non-empty result.
+//
+// If one of the functions fails, it stops immediately and the error is returned.
+// If all functions return the empty string, the empty string is returned.
+// If the context expires between the functions, the context error is returned.
+// If any of the functions is nil, ErrNilFunc is returned before running any of them.
+func CoalesceString(ctx context.Context, fns ...StringFunc) (string, error) {
+	for _, fn := range fns {
+		if fn == nil {
+			return "", ErrNilFunc
+		}
+	}
+
+	for _, fn := range fns {
+		item, err := fn(ctx)
+		if err != nil {
+			return "", err
+		}
+		if item != "" {
+			return item, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
 type Flow struct {
 	executor Executor
-}
 
-func New(executor Executor) *Flow {
-	return &Flow{executor}
+	// contextExecutor indicates whether an Executor attached to the context takes precedence.
+	contextExecutor bool
+	// aggregator combines the errors of a parallel execution. If nil, all errors are kept.
+	aggregator func([]error) error
+	// ignoreContextErrors makes Parallel and its typed variants treat context errors as success.
+	ignoreContextErrors bool
+
+	progressInterval time.Duration
+	progressCallback func(done, total int)
+
+	maxFanout       int
+	requireDeadline bool
+
+	startSpan func(ctx context.Context, name string) (context.Context, func(error))
+
+	orderedResults bool
+	resultBuffer   int
+
+	batchInfo bool
+
+	drainTimeout time.Duration
+
+	racePriority bool
+
+	warnDuplicate func(format string, args ...interface{})
+
+	// inFlight counts the submitted functions that did not complete yet. It is shared with the copies
+	// of the Flow.
+	inFlight *int64
+
+	// defaultContext is the context of the context-less operations. If nil, context.Background is used.
+	defaultContext context.Context
 }
 
-func (f *Flow) runAll(l int, run func(i int), deferred func()) {
-	if l == 0 {
-		return
+// Option configures a Flow.
+type Option func(*Flow)
+
+// WithErrorAggregator makes the Flow combine the errors of parallel executions with the given function.
+//
+// The aggregator is only called if at least one error occurred. Only the default aggregation
+// (see AllErrors) supports `ErrorsByIndex`.
+func WithErrorAggregator(aggregator func([]error) error) Option {
+	return func(f *Flow) {
+		f.aggregator = aggregator
 	}
+}
 
-	var wg sync.WaitGroup
-	wg.Add(l)
-	for i := 0; i < l; i++ {
-		i := i
-		f.executor.Submit(func() {
-			defer wg.Done()
-			run(i)
-		})
+// WithIgnoreContextErrors makes Parallel, ParallelOn, ParallelString, ParallelInt and ParallelBool treat
+// functions failing with context.Canceled or context.DeadlineExceeded as successful.
+//
+// Context errors are ignored regardless of which context was cancelled, which suits cleanup
+// fan-outs where cancellation is expected. The typed variants keep the results of such functions.
+func WithIgnoreContextErrors() Option {
+	return func(f *Flow) {
+		f.ignoreContextErrors = true
 	}
+}
 
-	go func() {
-		defer deferred()
-		wg.Wait()
-	}()
+// ignoresError reports whether err is not considered a failure of a function.
+func (f *Flow) ignoresError(err error) bool {
+	return f.ignoreContextErrors && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded))
 }
 
-// Parallel runs the given functions in parallel.
+// WithOrderedResults makes ParallelString, ParallelInt, ParallelBool and their CancelOnError variants
+// return the results in the order of the given functions if ordered is true.
 //
-// It collects all the errors in the returned error. To obtain
-// the multiple errors, use the `Errors` function.
-func (f *Flow) Parallel(ctx context.Context, fns ...Func) error {
-	if len(fns) == 0 {
-		return nil
+// The i-th result then holds the result of the i-th function; the slots of failed functions hold
+// the zero value. The errors are collected the same way regardless of the order of the results.
+func WithOrderedResults(ordered bool) Option {
+	return func(f *Flow) {
+		f.orderedResults = ordered
 	}
+}
 
-	results := make(chan error)
-	f.runAll(len(fns), func(i int) {
-		results <- fns[i](ctx)
-	}, func() { close(results) })
+// WithResultBuffer makes Parallel, ParallelOn, ParallelString, ParallelInt, ParallelBool and ParallelFold
+// buffer at most n results that were not collected yet.
+//
+// Functions whose results cannot be buffered block until the caller collected earlier results. With
+// a LimitingExecutor, blocked functions keep occupying their slots, so no further functions are
+// started while the collection lags behind. The Executor has to run the functions asynchronously,
+// as functions run on the calling goroutine block it forever once the buffer is full. If n is not
+// positive, all results are buffered.
+func WithResultBuffer(n int) Option {
+	return func(f *Flow) {
+		f.resultBuffer = n
+	}
+}
 
-	var errs multiError
-	for err := range results {
-		if err != nil {
-			errs = append(errs, err)
+// resultBufferFor returns the size of the buffer for the results of l functions.
+func (f *Flow) resultBufferFor(l int) int {
+	if f.resultBuffer > 0 && f.resultBuffer < l {
+		return f.resultBuffer
+	}
+	return l
+}
+
+func New(executor Executor, opts ...Option) *Flow {
+	f := &Flow{executor: executor, inFlight: new(int64)}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// aggregate combines the given errors using the aggregator of the Flow.
+func (f *Flow) aggregate(errs multiError) error {
+	if f.aggregator == nil || len(errs) == 0 {
+		return errs.ErrorOrNil()
+	}
+	return f.aggregator(Errors(errs))
+}
+
+// Executor returns the Executor the functions of f are submitted to.
+func (f *Flow) Executor() Executor {
+	return f.executor
+}
+
+// executorFor returns the Executor to use for an operation running with the given context.
+func (f *Flow) executorFor(ctx context.Context) Executor {
+	if f.contextExecutor {
+		if executor, ok := ExecutorFromContext(ctx); ok {
+			return executor
 		}
 	}
-	return errs.ErrorOrNil()
+	return f.executor
 }
 
-// ParallelCancelOnError runs the given functions in parallel, cancelling all if one fails.
+// InFlight returns the number of functions submitted by operations of f that did not complete yet.
 //
-// It collects all the errors in the returned error. To obtain
-// the multiple errors, use the `Errors` function.
-func (f *Flow) ParallelCancelOnError(ctx context.Context, fns ...Func) error {
-	if len(fns) == 0 {
-		return nil
+// Functions that were abandoned by an operation, e.g. by ParallelFailFast or after a drain timeout,
+// are counted until they complete, which helps detecting leaked goroutines in tests. Functions
+// spawned into a Scope, Nursery or FlowGroup are not counted. The count is shared with the copies
+// of f created by WithDefaultContext.
+func (f *Flow) InFlight() int {
+	if f.inFlight == nil {
+		return 0
 	}
+	return int(atomic.LoadInt64(f.inFlight))
+}
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+// track counts a submitted function as in flight, returning the function to call once it completed.
+func (f *Flow) track() func() {
+	if f.inFlight == nil {
+		return func() {}
+	}
+	atomic.AddInt64(f.inFlight, 1)
+	return func() { atomic.AddInt64(f.inFlight, -1) }
+}
 
-	results := make(chan error)
-	f.runAll(len(fns), func(i int) {
-		err := fns[i](ctx)
-		results <- err
-	}, func() { close(results) })
+// runsInline reports whether a single function may be run on the calling goroutine instead of
+// being submitted to executor.
+//
+// This only holds for the UnlimitedExecutor and the SyncExecutor, which neither limit nor observe
+// the functions, and if no progress is reported.
+func (f *Flow) runsInline(executor Executor) bool {
+	switch executor.(type) {
+	case plainExecutor, syncExecutor:
+		return f.progressCallback == nil
+	default:
+		return false
+	}
+}
 
-	var errs multiError
-	for err := range results {
-		if err != nil {
-			cancel()
-			errs = append(errs, err)
+func (f *Flow) runAll(ctx context.Context, l int, run func(ctx context.Context, i int), deferred func()) {
+	f.runOn(ctx, f.executorFor(ctx), l, run, deferred)
+}
+
+// batch tracks the completion of the functions submitted by runOn.
+type batch struct {
+	remaining int64 // accessed atomically
+	contextOf func(i int) context.Context
+	run       func(ctx context.Context, i int)
+	deferred  func()
+	progress  *progress
+	inFlight  *int64
+}
+
+// exec runs the function with the given index and completes it, even if it panics.
+func (b *batch) exec(i int) {
+	defer b.complete()
+	b.run(b.contextOf(i), i)
+}
+
+// complete marks a function as completed, finishing the batch once all functions completed.
+func (b *batch) complete() {
+	b.progress.complete()
+	// The function stops being in flight before the caller may be released by deferred.
+	if b.inFlight != nil {
+		atomic.AddInt64(b.inFlight, -1)
+	}
+	if atomic.AddInt64(&b.remaining, -1) == 0 {
+		b.progress.finish()
+		b.deferred()
+	}
+}
+
+// runOn submits run for every index below l to the given executor, calling deferred once all completed.
+//
+// run is called with ctx, carrying the batch information if f provides it. deferred is called by
+// the last function to complete, which avoids a separate goroutine waiting for the functions.
+func (f *Flow) runOn(ctx context.Context, executor Executor, l int, run func(ctx context.Context, i int), deferred func()) {
+	if l == 0 {
+		return
+	}
+
+	b := &batch{
+		remaining: int64(l),
+		contextOf: func(i int) context.Context { return f.withBatchInfo(ctx, i, l) },
+		run:       run,
+		deferred:  deferred,
+		progress:  f.startProgress(l),
+		inFlight:  f.inFlight,
+	}
+	if b.inFlight != nil {
+		atomic.AddInt64(b.inFlight, int64(l))
+	}
+	for i := 0; i < l; i++ {
+		i := i
+		executor.Submit(func() { b.exec(i) })
+	}
+}
+
+type batchInfoKey struct{}
+
+type batchInfo struct {
+	index, total int
+}
+
+// WithBatchInfo makes the Flow provide the index and the total number of functions to every function
+// it runs in parallel, e.g. by Parallel, ParallelString, RaceString or MapN. Use BatchInfo to read
+// them from the context of a function.
+//
+// Without this option, the functions are run with the context passed by the caller as is.
+func WithBatchInfo() Option {
+	return func(f *Flow) {
+		f.batchInfo = true
+	}
+}
+
+func (f *Flow) withBatchInfo(ctx context.Context, index, total int) context.Context {
+	if !f.batchInfo {
+		return ctx
+	}
+	return context.WithValue(ctx, batchInfoKey{}, batchInfo{index, total})
+}
+
+// BatchInfo returns the index of the function run with ctx and the total number of functions of its batch.
+//
+// The information is available to the functions run in parallel by a Flow created with
+// WithBatchInfo. For other contexts, ok is false.
+func BatchInfo(ctx context.Context) (index, total int, ok bool) {
+	info, ok := ctx.Value(batchInfoKey{}).(batchInfo)
+	return info.index, info.total, ok
+}
+
+// WithDefaultContext returns a copy of f whose context-less operations like ParallelBG use ctx.
+//
+// This is useful if the operations always run with the same context, e.g. one carrying tracing
+// or authentication information. The operations taking a context are not affected.
+func (f *Flow) WithDefaultContext(ctx context.Context) *Flow {
+	c := *f
+	c.defaultContext = ctx
+	return &c
+}
+
+// backgroundContext returns the default context of f or, if it has none, context.Background.
+func (f *Flow) backgroundContext() context.Context {
+	if f.defaultContext != nil {
+		return f.defaultContext
+	}
+	return context.Background()
+}
+
+// HandleSignals returns a copy of the default context of f that is cancelled once one of the given
+// signals arrives, so that passing it to the operations of f cancels their functions on shutdown.
+//
+// If no signals are given, os.Interrupt and syscall.SIGTERM cancel the context. Calling the returned
+// CancelFunc cancels the context and stops relaying the signals to it; it should be called as soon
+// as the context is not needed anymore, e.g. deferred in main.
+func (f *Flow) HandleSignals(signals ...os.Signal) (context.Context, context.CancelFunc) {
+	if len(signals) == 0 {
+		// Relaying all signals would include the ones used by the runtime, like SIGURG for preemption.
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	return signal.NotifyContext(f.backgroundContext(), signals...)
+}
+
+// Parallel runs the given functions in parallel.
+//
+// It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) Parallel(ctx context.Context, fns ...Func) error {
+	return f.ParallelOn(ctx, f.executorFor(ctx), fns...)
+}
+
+// ParallelBG runs the given functions in parallel with the default context of the Flow.
+//
+// See WithDefaultContext for the default context and Parallel for the semantics.
+func (f *Flow) ParallelBG(fns ...Func) error {
+	return f.Parallel(f.backgroundContext(), fns...)
+}
+
+// ParallelFunc returns a Func that runs the given functions in parallel when invoked.
+//
+// See Parallel for the semantics of the returned Func.
+func (f *Flow) ParallelFunc(fns ...Func) Func {
+	return func(ctx context.Context) error {
+		return f.Parallel(ctx, fns...)
+	}
+}
+
+// ParallelOn runs the given functions in parallel on the given executor instead of the one of the Flow.
+//
+// It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelOn(ctx context.Context, executor Executor, fns ...Func) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+	f.warnDuplicates(fns)
+	if len(fns) == 1 && f.runsInline(executor) {
+		fn := fns[0]
+		if fn == nil {
+			return nil
+		}
+
+		defer f.track()()
+		if err := withIndex(0, f.trace(f.withBatchInfo(ctx, 0, 1), "parallel", 0, fn)); err != nil && !f.ignoresError(err) {
+			return f.aggregate(multiError{err})
+		}
+		return nil
+	}
+
+	results := make(chan error, f.resultBufferFor(len(fns)))
+	f.runOn(ctx, executor, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			results <- withIndex(i, f.trace(ctx, "parallel", i, fn))
+		}
+	}, func() { close(results) })
+
+	var errs multiError
+	for err := range results {
+		if err != nil && !f.ignoresError(err) {
+			errs = append(errs, err)
+		}
+	}
+	return f.aggregate(errs)
+}
+
+// ParallelCancelOnError runs the given functions in parallel, cancelling all if one fails.
+//
+// It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelCancelOnError(ctx context.Context, fns ...Func) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan error, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			results <- withIndex(i, markCancellation(parent, ctx, fn(ctx)))
+		}
+	}, func() { close(results) })
+
+	var errs multiError
+	for err := range results {
+		if err != nil {
+			if len(errs) == 0 {
+				cancel()
+			}
+			errs = append(errs, err)
+		}
+	}
+	return f.aggregate(errs)
+}
+
+// ParallelUntilErrors runs the given functions in parallel, cancelling all once maxErrors of them failed.
+//
+// This tolerates up to maxErrors-1 failures; if maxErrors is not positive, it behaves like
+// ParallelCancelOnError. Errors caused by the cancellation are marked as *CancellationError.
+// It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelUntilErrors(ctx context.Context, maxErrors int, fns ...Func) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan error, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			results <- withIndex(i, markCancellation(parent, ctx, fn(ctx)))
+		}
+	}, func() { close(results) })
+
+	var errs multiError
+	for err := range results {
+		if err != nil {
+			errs = append(errs, err)
+			if len(errs) == maxErrors || maxErrors <= 0 {
+				cancel()
+			}
+		}
+	}
+	return f.aggregate(errs)
+}
+
+// ParallelFailFast runs the given functions in parallel, returning the first error as soon as it occurs.
+//
+// The remaining functions are neither cancelled nor waited for but continue running detached
+// from the caller. If all functions succeed, nil is returned once all of them completed.
+func (f *Flow) ParallelFailFast(ctx context.Context, fns ...Func) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	results := make(chan error, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			results <- fn(ctx)
+		}
+	}, func() { close(results) })
+
+	for err := range results {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParallelGraceful runs the given functions in parallel, giving them a grace period to complete once the context is done.
+//
+// The functions run with ctx, so its cancellation signals them to stop. Once ctx is done, the
+// functions are given grace to complete, e.g. to flush their state, before ParallelGraceful
+// returns. Functions that did not complete by then are abandoned; they continue running detached
+// from the caller and the error of ctx is collected in place of each of them. It collects all the
+// errors in the returned error. To obtain the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelGraceful(ctx context.Context, grace time.Duration, fns ...Func) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	results := make(chan indexedError, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			results <- indexedError{i, fn(ctx)}
+		}
+	}, func() { close(results) })
+
+	var (
+		errs      multiError
+		completed = make([]bool, len(fns))
+		done      = ctx.Done()
+		expired   <-chan struct{}
+	)
+	for i, fn := range fns {
+		completed[i] = fn == nil
+	}
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				return f.aggregate(errs)
+			}
+			completed[res.index] = true
+			if res.err != nil {
+				errs = append(errs, res)
+			}
+		case <-done:
+			done = nil
+			// The grace period must not end with ctx, so it is measured on a detached context.
+			graceCtx, cancel := context.WithTimeout(detachedContext{ctx}, grace)
+			defer cancel()
+			expired = graceCtx.Done()
+		case <-expired:
+			for i, ok := range completed {
+				if !ok {
+					errs = append(errs, indexedError{i, ctx.Err()})
+				}
+			}
+			return f.aggregate(errs)
+		}
+	}
+}
+
+// detachedContext is a context that carries the values of its parent but is never cancelled.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// ErrLengthMismatch is returned if the lengths of slices that have to correspond differ.
+var ErrLengthMismatch = errors.New("length mismatch")
+
+// ParallelTimeouts runs the given functions in parallel, each with its own timeout.
+//
+// The i-th function runs with a context that expires after timeouts[i]; a zero timeout means the
+// function only observes the context passed to ParallelTimeouts. If the number of timeouts differs
+// from the number of functions, an error wrapping ErrLengthMismatch is returned without running
+// any of them. It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelTimeouts(ctx context.Context, timeouts []time.Duration, fns ...Func) error {
+	if len(timeouts) != len(fns) {
+		return fmt.Errorf("%w: %d timeouts for %d functions", ErrLengthMismatch, len(timeouts), len(fns))
+	}
+
+	timed := make([]Func, len(fns))
+	for i, fn := range fns {
+		if fn == nil || timeouts[i] == 0 {
+			timed[i] = fn
+			continue
+		}
+
+		fn, timeout := fn, timeouts[i]
+		timed[i] = func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return fn(ctx)
+		}
+	}
+	return f.Parallel(ctx, timed...)
+}
+
+// ParallelTry runs the given functions in parallel, waiting at most d for them to complete.
+//
+// It returns the number of functions that completed within d together with the errors of those that
+// failed. Functions that did not complete in time are abandoned: they are neither cancelled nor
+// waited for but continue running detached from the caller. It collects all the errors in the
+// returned error. To obtain the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelTry(ctx context.Context, d time.Duration, fns ...Func) (completed int, err error) {
+	if len(fns) == 0 {
+		return 0, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return 0, err
+	}
+
+	results := make(chan error, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			results <- withIndex(i, fn(ctx))
+		}
+	}, func() { close(results) })
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	var errs multiError
+	for {
+		select {
+		case err, ok := <-results:
+			if !ok {
+				return completed, f.aggregate(errs)
+			}
+			completed++
+			if err != nil {
+				errs = append(errs, err)
+			}
+		case <-timer.C:
+			return completed, f.aggregate(errs)
+		}
+	}
+}
+
+// FuncResult describes the execution of a single function of a parallel execution.
+type FuncResult struct {
+	// Index is the index of the function.
+	Index int
+	// Duration is the time the function took to complete.
+	Duration time.Duration
+	// Err is the error returned by the function.
+	Err error
+}
+
+// ParallelTimed runs the given functions in parallel, returning the error and duration of each of them.
+//
+// The i-th result describes the execution of the i-th function. If the Flow rejects the execution,
+// e.g. because its maximum fan-out is exceeded, no function is run and every result carries that error.
+func (f *Flow) ParallelTimed(ctx context.Context, fns ...Func) []FuncResult {
+	if len(fns) == 0 {
+		return nil
+	}
+
+	var (
+		out  = make([]FuncResult, len(fns))
+		done = make(chan struct{})
+	)
+	if err := f.check(ctx, len(fns)); err != nil {
+		for i := range out {
+			out[i] = FuncResult{Index: i, Err: err}
+		}
+		return out
+	}
+
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		out[i].Index = i
+		if fn := fns[i]; fn != nil {
+			start := time.Now()
+			out[i].Err = fn(ctx)
+			out[i].Duration = time.Since(start)
+		}
+	}, func() { close(done) })
+
+	<-done
+	return out
+}
+
+// ParallelWithCancel runs the given functions in parallel, passing each of them the cancel function of the group.
+//
+// Any function may cancel all functions of the group by calling the cancel function, regardless of
+// whether it fails or not. It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelWithCancel(ctx context.Context, fns ...func(context.Context, context.CancelFunc) error) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan error, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			results <- withIndex(i, markCancellation(parent, ctx, fn(ctx, cancel)))
+		}
+	}, func() { close(results) })
+
+	var errs multiError
+	for err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return f.aggregate(errs)
+}
+
+// ParallelHandle starts running the given functions in parallel and returns without waiting for them.
+//
+// Calling wait blocks until all functions completed and returns the collected errors. To obtain
+// the multiple errors, use the `Errors` function. Calling cancel cancels the context of all
+// functions, e.g. from another goroutine; the resulting cancellations are reported as
+// CancellationError. Both may be called any number of times.
+func (f *Flow) ParallelHandle(ctx context.Context, fns ...Func) (wait func() error, cancel func()) {
+	parent := ctx
+	ctx, cancelCtx := context.WithCancel(ctx)
+
+	var (
+		err  error
+		done = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		defer cancelCtx()
+		err = f.Parallel(ctx, markCancellations(parent, fns)...)
+	}()
+
+	return func() error {
+		<-done
+		return err
+	}, cancelCtx
+}
+
+// markCancellations wraps the given functions so that their errors are marked using markCancellation.
+// Nil functions are kept as is.
+func markCancellations(parent context.Context, fns []Func) []Func {
+	out := make([]Func, len(fns))
+	for i, fn := range fns {
+		if fn == nil {
+			continue
+		}
+		fn := fn
+		out[i] = func(ctx context.Context) error {
+			return markCancellation(parent, ctx, fn(ctx))
+		}
+	}
+	return out
+}
+
+// Stages runs the given stages one after another, running the functions of each stage in parallel.
+//
+// The next stage is only started if all functions of the prior stage succeeded. Otherwise, the
+// collected errors of the failing stage are returned. Empty stages are skipped.
+// If the context expires between the stages, the context error is returned.
+func (f *Flow) Stages(ctx context.Context, stages ...[]Func) error {
+	for _, stage := range stages {
+		if err := f.Parallel(ctx, stage...); err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flatten concatenates the given groups of functions into one slice.
+func Flatten(groups ...[]Func) []Func {
+	var n int
+	for _, group := range groups {
+		n += len(group)
+	}
+
+	out := make([]Func, 0, n)
+	for _, group := range groups {
+		out = append(out, group...)
+	}
+	return out
+}
+
+// GroupError is returned by ParallelGroups if functions of any group failed.
+type GroupError struct {
+	// Groups holds the errors of the failed functions by the index of their group.
+	Groups map[int][]error
+}
+
+// Error implements error.
+func (e *GroupError) Error() string {
+	groups := make([]int, 0, len(e.Groups))
+	for group := range e.Groups {
+		groups = append(groups, group)
+	}
+	sort.Ints(groups)
+
+	var buf strings.Builder
+	for i, group := range groups {
+		for j, err := range e.Groups[group] {
+			if i > 0 || j > 0 {
+				_, _ = fmt.Fprintln(&buf)
+			}
+			_, _ = fmt.Fprintf(&buf, "group %d: %v", group, err)
+		}
+	}
+	return buf.String()
+}
+
+// ParallelGroups runs the functions of all given groups in parallel as one batch.
+//
+// If any function fails, a *GroupError is returned that holds the errors by the index of the group
+// of the failed function. The error aggregator of the Flow is not applied.
+func (f *Flow) ParallelGroups(ctx context.Context, groups ...[]Func) error {
+	var (
+		fns     = Flatten(groups...)
+		groupOf = make([]int, 0, len(fns))
+	)
+	for i, group := range groups {
+		for range group {
+			groupOf = append(groupOf, i)
+		}
+	}
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	results := make(chan indexedError, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			if err := fn(ctx); err != nil {
+				results <- indexedError{i, err}
+			}
+		}
+	}, func() { close(results) })
+
+	var errs map[int][]error
+	for res := range results {
+		if errs == nil {
+			errs = make(map[int][]error)
+		}
+		group := groupOf[res.index]
+		errs[group] = append(errs[group], res.err)
+	}
+	if errs == nil {
+		return nil
+	}
+	return &GroupError{errs}
+}
+
+// NamedGroups runs the functions of all given named groups in parallel as one batch, cancelling all once one fails.
+//
+// The first failure cancels the context of all functions with a cause identifying the group of the
+// failed function, like `group "name" failed: <error>`, which the functions can obtain using
+// context.Cause. Once all functions completed, that cause is returned; it wraps the error of the
+// failed function.
+func (f *Flow) NamedGroups(ctx context.Context, groups map[string][]Func) error {
+	var (
+		fns    []Func
+		nameOf []string
+	)
+	for name, group := range groups {
+		for _, fn := range group {
+			fns = append(fns, fn)
+			nameOf = append(nameOf, name)
+		}
+	}
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		once  sync.Once
+		cause error
+		done  = make(chan struct{})
+	)
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			if err := fn(ctx); err != nil {
+				once.Do(func() {
+					cause = fmt.Errorf("group %q failed: %w", nameOf[i], err)
+					cancel(cause)
+				})
+			}
+		}
+	}, func() { close(done) })
+
+	<-done
+	return cause
+}
+
+// ParallelNamed runs the given named functions in parallel and returns the errors of the failed
+// functions by their names.
+//
+// The names of the succeeded functions are omitted, so that the returned map is empty if all
+// functions succeeded. If the Flow rejects the execution, e.g. because its maximum fan-out is
+// exceeded, none of the functions is run and each is reported with that error.
+func (f *Flow) ParallelNamed(ctx context.Context, fns map[string]Func) map[string]error {
+	if len(fns) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(fns))
+	for name := range fns {
+		names = append(names, name)
+	}
+	if err := f.check(ctx, len(names)); err != nil {
+		errs := make(map[string]error, len(names))
+		for _, name := range names {
+			errs[name] = err
+		}
+		return errs
+	}
+
+	results := make(chan indexedError, len(names))
+	f.runAll(ctx, len(names), func(ctx context.Context, i int) {
+		if fn := fns[names[i]]; fn != nil {
+			results <- indexedError{i, fn(ctx)}
+		}
+	}, func() { close(results) })
+
+	errs := make(map[string]error)
+	for res := range results {
+		if res.err != nil {
+			errs[names[res.index]] = res.err
+		}
+	}
+	return errs
+}
+
+// Race runs all functions in parallel and returns the first that completes.
+//
+// Completion means a function either errors or succeeds.
+// The result of the succeeded function is returned, the other results are
+// discarded.
+func (f *Flow) Race(ctx context.Context, fns ...Func) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	executor := f.executorFor(ctx)
+	if len(fns) == 1 && f.runsInline(executor) {
+		fn := fns[0]
+		if fn == nil {
+			return nil
+		}
+
+		defer f.track()()
+		return fn(f.withBatchInfo(ctx, 0, 1))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan indexedError, len(fns))
+	f.runOn(ctx, executor, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			results <- indexedError{i, fn(ctx)}
+		}
+	}, func() { close(results) })
+
+	res := prioritize(f, results, <-results, func(res indexedError) int { return res.index })
+	cancel()
+	drain(f, results, nil)
+	return res.err
+}
+
+type stringResult struct {
+	index int
+	item  string
+	err   error
+}
+
+// ParallelString runs the given functions in parallel.
+//
+// It collects all the errors and results (regardless if there were errors or not). To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelString(ctx context.Context, fns ...StringFunc) ([]string, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
+
+	c := make(chan stringResult, f.resultBufferFor(len(fns)))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- stringResult{i, item, err}
+		}
+	}, func() { close(c) })
+
+	var (
+		out  []string
+		errs multiError
+	)
+	if f.orderedResults {
+		out = make([]string, len(fns))
+	}
+	for res := range c {
+		if res.err != nil && !f.ignoresError(res.err) {
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+		if f.orderedResults {
+			out[res.index] = res.item
+			continue
+		}
+		out = append(out, res.item)
+	}
+	return out, f.aggregate(errs)
+}
+
+// ParallelStringCancelOnError runs the given functions in parallel, cancelling all if one fails.
+//
+// Results are processed in the order they are received. The returned results are exactly the
+// successful results received before the first error; later results are discarded, but all
+// errors are collected. To obtain the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelStringCancelOnError(ctx context.Context, fns ...StringFunc) ([]string, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := make(chan stringResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- stringResult{i, item, markCancellation(parent, ctx, err)}
+		}
+	}, func() { close(c) })
+
+	var (
+		out  []string
+		errs multiError
+	)
+	if f.orderedResults {
+		out = make([]string, len(fns))
+	}
+	for res := range c {
+		if res.err != nil {
+			if len(errs) == 0 {
+				cancel()
+			}
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+		if len(errs) > 0 {
+			continue
+		}
+		if f.orderedResults {
+			out[res.index] = res.item
+			continue
+		}
+		out = append(out, res.item)
+	}
+	return out, f.aggregate(errs)
+}
+
+// ParallelStringIndexed runs the given functions in parallel, returning their results and errors by index.
+//
+// The i-th result holds the result of the i-th function, or the empty string if it failed.
+// The errors of the failed functions are returned by their index; if no function failed, the
+// returned map is nil. If the Flow rejects the execution, e.g. because its maximum fan-out is
+// exceeded, no function is run and that error is returned for every index.
+func (f *Flow) ParallelStringIndexed(ctx context.Context, fns ...StringFunc) ([]string, map[int]error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		errs := make(map[int]error, len(fns))
+		for i := range fns {
+			errs[i] = err
+		}
+		return make([]string, len(fns)), errs
+	}
+
+	c := make(chan stringResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- stringResult{i, item, err}
+		}
+	}, func() { close(c) })
+
+	var (
+		out  = make([]string, len(fns))
+		errs map[int]error
+	)
+	for res := range c {
+		if res.err != nil {
+			if errs == nil {
+				errs = make(map[int]error)
+			}
+			errs[res.index] = res.err
+			continue
+		}
+		out[res.index] = res.item
+	}
+	return out, errs
+}
+
+// ParallelNamedString runs the given named functions in parallel, returning their results and errors by name.
+//
+// The results of the succeeded functions and the errors of the failed functions are returned in
+// separate maps; if no function failed, the returned error map is nil. If the Flow rejects the
+// execution, e.g. because its maximum fan-out is exceeded, no function is run and that error is
+// returned for every name.
+func (f *Flow) ParallelNamedString(ctx context.Context, fns map[string]StringFunc) (map[string]string, map[string]error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(fns))
+	for name := range fns {
+		names = append(names, name)
+	}
+	if err := f.check(ctx, len(names)); err != nil {
+		errs := make(map[string]error, len(names))
+		for _, name := range names {
+			errs[name] = err
+		}
+		return nil, errs
+	}
+
+	c := make(chan stringResult, len(names))
+	f.runAll(ctx, len(names), func(ctx context.Context, i int) {
+		if fn := fns[names[i]]; fn != nil {
+			item, err := fn(ctx)
+			c <- stringResult{i, item, err}
+		}
+	}, func() { close(c) })
+
+	var (
+		out  = make(map[string]string, len(names))
+		errs map[string]error
+	)
+	for res := range c {
+		if res.err != nil {
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+			errs[names[res.index]] = res.err
+			continue
+		}
+		out[names[res.index]] = res.item
+	}
+	return out, errs
+}
+
+// ErrPartialDeadline is returned by ParallelStringWithDeadline if not all functions completed before the deadline.
+var ErrPartialDeadline = errors.New("deadline reached before all functions completed")
+
+// ParallelStringWithDeadline runs the given functions in parallel, collecting the results that arrive within d.
+//
+// Once d elapsed, the remaining functions are cancelled and the results collected so far are returned
+// along with ErrPartialDeadline. The same applies if the context expires before. Results that were
+// already sent when the deadline is reached are still collected.
+// It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelStringWithDeadline(ctx context.Context, d time.Duration, fns ...StringFunc) ([]string, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
+
+	var n int
+	for _, fn := range fns {
+		if fn != nil {
+			n++
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	c := make(chan stringResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- stringResult{i, item, err}
+		}
+	}, func() { close(c) })
+
+	var (
+		out       []string
+		errs      multiError
+		completed int
+	)
+	collect := func(res stringResult) {
+		completed++
+		if res.err != nil {
+			errs = append(errs, indexedError{res.index, res.err})
+			return
+		}
+		out = append(out, res.item)
+	}
+
+	for expired := false; !expired && completed < n; {
+		select {
+		case res := <-c:
+			collect(res)
+		case <-ctx.Done():
+			expired = true
+		}
+	}
+	for drained := false; !drained && completed < n; {
+		select {
+		case res := <-c:
+			collect(res)
+		default:
+			drained = true
+		}
+	}
+
+	if completed < n {
+		errs = append(errs, ErrPartialDeadline)
+	}
+	return out, f.aggregate(errs)
+}
+
+// RaceString runs all functions in parallel and returns the results of the first that completes.
+//
+// Completion means a function either errors or succeeds.
+// The result of the succeeded function is returned, the other results are
+// discarded.
+func (f *Flow) RaceString(ctx context.Context, fns ...StringFunc) (string, error) {
+	if len(fns) == 0 {
+		return "", nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan stringResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- stringResult{i, item, err}
+		}
+	}, func() { close(results) })
+
+	res := prioritize(f, results, <-results, func(res stringResult) int { return res.index })
+	cancel()
+	drain(f, results, nil)
+	return res.item, res.err
+}
+
+// FirstString runs all functions in parallel and returns the result of the first that succeeds.
+//
+// Once a function succeeded, the remaining ones are cancelled and their results are discarded.
+// If all functions fail, their errors are collected in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) FirstString(ctx context.Context, fns ...StringFunc) (string, error) {
+	if len(fns) == 0 {
+		return "", nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan stringResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- stringResult{i, item, err}
+		}
+	}, func() { close(results) })
+
+	var errs multiError
+	for res := range results {
+		if res.err != nil {
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+
+		cancel()
+		drain(f, results, nil)
+		return res.item, nil
+	}
+	return "", f.aggregate(errs)
+}
+
+// RaceStringSuccess runs all functions in parallel and returns the result of the first that succeeds.
+//
+// Unlike RaceString, failed completions do not end the race. Once a function succeeded, the
+// remaining ones are cancelled and their results are discarded. If all functions fail, the error
+// of the function that failed last is returned.
+func (f *Flow) RaceStringSuccess(ctx context.Context, fns ...StringFunc) (string, error) {
+	if len(fns) == 0 {
+		return "", nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan stringResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- stringResult{i, item, err}
+		}
+	}, func() { close(results) })
+
+	var last error
+	for res := range results {
+		if res.err != nil {
+			last = res.err
+			continue
+		}
+
+		cancel()
+		drain(f, results, nil)
+		return res.item, nil
+	}
+	return "", last
+}
+
+// ErrQuorumUnreachable is returned by QuorumString and VoteBool if fewer functions than required are given.
+var ErrQuorumUnreachable = errors.New("quorum unreachable")
+
+// QuorumString runs all functions in parallel and returns the results of the first k that succeed.
+//
+// Once k functions succeeded, the remaining ones are cancelled and their results are discarded.
+// If so many functions fail that k successes are no longer possible, the remaining functions are
+// cancelled as well and the errors are returned alongside the results obtained so far. To obtain
+// the multiple errors, use the `Errors` function. If fewer than k functions are given,
+// ErrQuorumUnreachable is returned without running any of them.
+func (f *Flow) QuorumString(ctx context.Context, k int, fns ...StringFunc) ([]string, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
+
+	var n int
+	for _, fn := range fns {
+		if fn != nil {
+			n++
+		}
+	}
+	if k > n {
+		return nil, ErrQuorumUnreachable
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan stringResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- stringResult{i, item, err}
+		}
+	}, func() { close(results) })
+
+	var (
+		out  []string
+		errs multiError
+	)
+	for res := range results {
+		if res.err != nil {
+			errs = append(errs, indexedError{res.index, res.err})
+			if len(errs) > n-k {
+				break
+			}
+			continue
+		}
+
+		out = append(out, res.item)
+		if len(out) == k {
+			break
+		}
+	}
+	cancel()
+	drain(f, results, nil)
+
+	if len(out) < k {
+		return out, f.aggregate(errs)
+	}
+	return out, nil
+}
+
+type intResult struct {
+	index int
+	item  int
+	err   error
+}
+
+// ParallelInt runs the given functions in parallel.
+//
+// It collects all the errors and results (regardless if there were errors or not). To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelInt(ctx context.Context, fns ...IntFunc) ([]int, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
+
+	c := make(chan intResult, f.resultBufferFor(len(fns)))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- intResult{i, item, err}
+		}
+	}, func() { close(c) })
+
+	var (
+		out  []int
+		errs multiError
+	)
+	if f.orderedResults {
+		out = make([]int, len(fns))
+	}
+	for res := range c {
+		if res.err != nil && !f.ignoresError(res.err) {
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+		if f.orderedResults {
+			out[res.index] = res.item
+			continue
+		}
+		out = append(out, res.item)
+	}
+	return out, f.aggregate(errs)
+}
+
+// ParallelIntInto runs the given functions in parallel, sending each successful result to out as it completes.
+//
+// out is not closed, as it is owned by the caller. The caller has to drain out concurrently:
+// if out is unbuffered or full and not drained, ParallelIntInto blocks forever.
+// It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelIntInto(ctx context.Context, out chan<- int, fns ...IntFunc) error {
+	if len(fns) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return err
+	}
+
+	c := make(chan intResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- intResult{i, item, err}
+		}
+	}, func() { close(c) })
+
+	var errs multiError
+	for res := range c {
+		if res.err != nil {
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+		out <- res.item
+	}
+	return f.aggregate(errs)
+}
+
+// ParallelIntCancelOnError runs the given functions in parallel, cancelling all if one fails.
+//
+// Results are processed in the order they are received. The returned results are exactly the
+// successful results received before the first error; later results are discarded, but all
+// errors are collected. To obtain the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelIntCancelOnError(ctx context.Context, fns ...IntFunc) ([]int, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := make(chan intResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- intResult{i, item, markCancellation(parent, ctx, err)}
+		}
+	}, func() { close(c) })
+
+	var (
+		out  []int
+		errs multiError
+	)
+	if f.orderedResults {
+		out = make([]int, len(fns))
+	}
+	for res := range c {
+		if res.err != nil {
+			if len(errs) == 0 {
+				cancel()
+			}
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+		if len(errs) > 0 {
+			continue
+		}
+		if f.orderedResults {
+			out[res.index] = res.item
+			continue
+		}
+		out = append(out, res.item)
+	}
+	return out, f.aggregate(errs)
+}
+
+// ParallelIntCancelOnErrorIndexed runs the given functions in parallel, cancelling all if one fails.
+//
+// The results of the functions that succeeded are returned by their index. Unlike
+// ParallelIntCancelOnError, errors caused by the cancellation are not collected, so the returned
+// error only holds the genuine failures. To obtain the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelIntCancelOnErrorIndexed(ctx context.Context, fns ...IntFunc) (map[int]int, error) {
+	if len(fns) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := make(chan intResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- intResult{i, item, markCancellation(parent, ctx, err)}
+		}
+	}, func() { close(c) })
+
+	var (
+		out  = make(map[int]int, len(fns))
+		errs multiError
+	)
+	for res := range c {
+		if res.err != nil {
+			var cerr *CancellationError
+			if errors.As(res.err, &cerr) {
+				continue
+			}
+			if len(errs) == 0 {
+				cancel()
+			}
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
 		}
+		out[res.index] = res.item
 	}
-	return errs.ErrorOrNil()
+	return out, f.aggregate(errs)
 }
 
-// Race runs all functions in parallel and returns the first that completes.
+// RaceInt runs all functions in parallel and returns the results of the first that completes.
 //
 // Completion means a function either errors or succeeds.
 // The result of the succeeded function is returned, the other results are
 // discarded.
-func (f *Flow) Race(ctx context.Context, fns ...Func) error {
+func (f *Flow) RaceInt(ctx context.Context, fns ...IntFunc) (int, error) {
+	value, _, err := f.RaceIntIndexed(ctx, fns...)
+	return value, err
+}
+
+// RaceIntIndexed runs all functions in parallel and returns the results and the index of the first that completes.
+//
+// Completion means a function either errors or succeeds.
+// The result of the succeeded function is returned, the other results are
+// discarded. If no function completed, the returned index is -1.
+func (f *Flow) RaceIntIndexed(ctx context.Context, fns ...IntFunc) (value, index int, err error) {
 	if len(fns) == 0 {
-		return nil
+		return 0, -1, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return 0, -1, err
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	results := make(chan error)
-	f.runAll(len(fns), func(i int) {
-		results <- fns[i](ctx)
+	results := make(chan intResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- intResult{i, item, err}
+		}
 	}, func() { close(results) })
 
-	err := <-results
+	res, ok := <-results
+	if ok {
+		res = prioritize(f, results, res, func(res intResult) int { return res.index })
+	}
 	cancel()
-	for range results {
+	drain(f, results, nil)
+	if !ok {
+		return 0, -1, nil
 	}
-	return err
+	return res.item, res.index, res.err
 }
 
-type stringResult struct {
-	item string
-	err  error
+type boolResult struct {
+	index int
+	item  bool
+	err   error
 }
 
-// ParallelString runs the given functions in parallel.
+// ParallelInt runs the given functions in parallel.
 //
 // It collects all the errors and results (regardless if there were errors or not). To obtain
 // the multiple errors, use the `Errors` function.
-func (f *Flow) ParallelString(ctx context.Context, fns ...StringFunc) ([]string, error) {
+func (f *Flow) ParallelBool(ctx context.Context, fns ...BoolFunc) ([]bool, error) {
 	if len(fns) == 0 {
 		return nil, nil
 	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
 
-	c := make(chan stringResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		c <- stringResult{item, err}
+	c := make(chan boolResult, f.resultBufferFor(len(fns)))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- boolResult{i, item, err}
+		}
 	}, func() { close(c) })
 
 	var (
-		out  []string
+		out  []bool
 		errs multiError
 	)
+	if f.orderedResults {
+		out = make([]bool, len(fns))
+	}
 	for res := range c {
-		if res.err != nil {
-			errs = append(errs, res.err)
+		if res.err != nil && !f.ignoresError(res.err) {
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+		if f.orderedResults {
+			out[res.index] = res.item
 			continue
 		}
 		out = append(out, res.item)
 	}
-	return out, errs.ErrorOrNil()
+	return out, f.aggregate(errs)
 }
 
-// ParallelStringCancelOnError runs the given functions in parallel, cancelling all if one fails.
+// ParallelBoolCancelOnError runs the given functions in parallel, cancelling all if one fails.
 //
-// It collects all the errors and results (regardless if there were errors or not). To obtain
-// the multiple errors, use the `Errors` function.
-func (f *Flow) ParallelStringCancelOnError(ctx context.Context, fns ...StringFunc) ([]string, error) {
+// Results are processed in the order they are received. The returned results are exactly the
+// successful results received before the first error; later results are discarded, but all
+// errors are collected. To obtain the multiple errors, use the `Errors` function.
+func (f *Flow) ParallelBoolCancelOnError(ctx context.Context, fns ...BoolFunc) ([]bool, error) {
 	if len(fns) == 0 {
 		return nil, nil
 	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
 
+	parent := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	c := make(chan stringResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		c <- stringResult{item, err}
+	c := make(chan boolResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			c <- boolResult{i, item, markCancellation(parent, ctx, err)}
+		}
 	}, func() { close(c) })
 
 	var (
-		out  []string
+		out  []bool
 		errs multiError
 	)
+	if f.orderedResults {
+		out = make([]bool, len(fns))
+	}
 	for res := range c {
 		if res.err != nil {
-			cancel()
-			errs = append(errs, res.err)
+			if len(errs) == 0 {
+				cancel()
+			}
+			errs = append(errs, indexedError{res.index, res.err})
+			continue
+		}
+		if len(errs) > 0 {
+			continue
+		}
+		if f.orderedResults {
+			out[res.index] = res.item
 			continue
 		}
 		out = append(out, res.item)
 	}
-	return out, errs.ErrorOrNil()
+	return out, f.aggregate(errs)
 }
 
-// RaceString runs all functions in parallel and returns the results of the first that completes.
+// RaceBool runs all functions in parallel and returns the results of the first that completes.
 //
 // Completion means a function either errors or succeeds.
 // The result of the succeeded function is returned, the other results are
 // discarded.
-func (f *Flow) RaceString(ctx context.Context, fns ...StringFunc) (string, error) {
+func (f *Flow) RaceBool(ctx context.Context, fns ...BoolFunc) (bool, error) {
 	if len(fns) == 0 {
-		return "", nil
+		return false, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return false, err
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	results := make(chan stringResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		results <- stringResult{item, err}
+	results := make(chan boolResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- boolResult{i, item, err}
+		}
 	}, func() { close(results) })
 
-	res := <-results
+	res := prioritize(f, results, <-results, func(res boolResult) int { return res.index })
 	cancel()
-	for range results {
-	}
+	drain(f, results, nil)
 	return res.item, res.err
 }
 
-type intResult struct {
-	item int
-	err  error
+type closerResult struct {
+	index int
+	item  io.Closer
+	err   error
 }
 
-// ParallelInt runs the given functions in parallel.
+// RaceCloser runs all functions in parallel and returns the results of the first that completes.
 //
-// It collects all the errors and results (regardless if there were errors or not). To obtain
-// the multiple errors, use the `Errors` function.
-func (f *Flow) ParallelInt(ctx context.Context, fns ...IntFunc) ([]int, error) {
+// Completion means a function either errors or succeeds.
+// The result of the succeeded function is returned, the other results are
+// discarded. Non-nil io.Closer results of the other functions are closed.
+func (f *Flow) RaceCloser(ctx context.Context, fns ...func(context.Context) (io.Closer, error)) (io.Closer, error) {
 	if len(fns) == 0 {
 		return nil, nil
 	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
 
-	c := make(chan intResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		c <- intResult{item, err}
-	}, func() { close(c) })
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	var (
-		out  []int
-		errs multiError
-	)
-	for res := range c {
-		if res.err != nil {
-			errs = append(errs, res.err)
-			continue
+	results := make(chan closerResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- closerResult{i, item, err}
 		}
-		out = append(out, res.item)
-	}
-	return out, errs.ErrorOrNil()
+	}, func() { close(results) })
+
+	res := <-results
+	cancel()
+	drain(f, results, func(loser closerResult) {
+		if loser.item != nil {
+			_ = loser.item.Close()
+		}
+	})
+	return res.item, res.err
 }
 
-// ParallelIntCancelOnError runs the given functions in parallel, cancelling all if one fails.
+// RaceAny runs all functions in parallel and returns the first that completes.
 //
-// It collects all the errors and results (regardless if there were errors or not). To obtain
-// the multiple errors, use the `Errors` function.
-func (f *Flow) ParallelIntCancelOnError(ctx context.Context, fns ...IntFunc) ([]int, error) {
+// Completion means a function either errors or succeeds.
+// The result of the succeeded function is returned, the other results are
+// discarded. Unlike the typed variants, the functions may return results of different types.
+func (f *Flow) RaceAny(ctx context.Context, fns ...func(context.Context) (interface{}, error)) (interface{}, error) {
 	if len(fns) == 0 {
 		return nil, nil
 	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return nil, err
+	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	c := make(chan intResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		c <- intResult{item, err}
-	}, func() { close(c) })
+	results := make(chan result[interface{}], len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- result[interface{}]{i, item, err}
+		}
+	}, func() { close(results) })
 
-	var (
-		out  []int
-		errs multiError
-	)
-	for res := range c {
-		if res.err != nil {
+	res := prioritize(f, results, <-results, func(res result[interface{}]) int { return res.index })
+	cancel()
+	drain(f, results, nil)
+	return res.item, res.err
+}
+
+// RaceCond runs all functions in parallel and returns the result of the first function that completes with an
+// error or with a truthy result.
+//
+// Results are considered in the order they are received, so if multiple functions qualify, the one
+// that completed first wins. As soon as a qualifying result is received, the remaining functions are
+// cancelled and their results are discarded. If no function qualifies, false is returned.
+func (f *Flow) RaceCond(ctx context.Context, fns ...BoolFunc) (bool, error) {
+	if len(fns) == 0 {
+		return false, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan boolResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- boolResult{i, item, err}
+		}
+	}, func() { close(results) })
+
+	var out boolResult
+	for res := range results {
+		if res.err != nil || res.item {
 			cancel()
-			errs = append(errs, res.err)
-			continue
+			out = res
+			break
 		}
-		out = append(out, res.item)
 	}
-	return out, errs.ErrorOrNil()
+	drain(f, results, nil)
+	return out.item, out.err
 }
 
-// RaceInt runs all functions in parallel and returns the results of the first that completes.
+type result[T any] struct {
+	index int
+	item  T
+	err   error
+}
+
+// RaceFirst runs all functions in parallel and returns the result of the first function that completes with an
+// error or with a result satisfying pred.
 //
-// Completion means a function either errors or succeeds.
-// The result of the succeeded function is returned, the other results are
-// discarded.
-func (f *Flow) RaceInt(ctx context.Context, fns ...IntFunc) (int, error) {
+// Results are considered in the order they are received. As soon as a qualifying result is received,
+// the remaining functions are cancelled and their results are discarded. If no function qualifies,
+// the zero value is returned.
+func RaceFirst[T any](ctx context.Context, f *Flow, pred func(T) bool, fns ...func(context.Context) (T, error)) (T, error) {
+	var out result[T]
 	if len(fns) == 0 {
-		return 0, nil
+		return out.item, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return out.item, err
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	results := make(chan intResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		results <- intResult{item, err}
+	results := make(chan result[T], len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- result[T]{i, item, err}
+		}
 	}, func() { close(results) })
 
-	res := <-results
-	cancel()
-	for range results {
+	for res := range results {
+		if res.err != nil || pred(res.item) {
+			cancel()
+			out = res
+			break
+		}
 	}
-	return res.item, res.err
+	drain(f, results, nil)
+	return out.item, out.err
 }
 
-type boolResult struct {
-	item bool
-	err  error
+// RaceAtLeast runs all functions in parallel and returns the best result among the first min that complete.
+//
+// The best result is the first successful one in the order the results are received, or the first
+// error if none of them succeeded. Once min functions completed, the remaining ones are cancelled
+// and their results are discarded. If fewer than min functions are given, all of them are waited
+// for. A min that is not positive is treated as 1.
+func RaceAtLeast[T any](ctx context.Context, f *Flow, min int, fns ...func(context.Context) (T, error)) (T, error) {
+	var out result[T]
+	if len(fns) == 0 {
+		return out.item, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return out.item, err
+	}
+	if min <= 0 {
+		min = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan result[T], len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- result[T]{i, item, err}
+		}
+	}, func() { close(results) })
+
+	var (
+		completed int
+		found     bool
+	)
+	for res := range results {
+		switch {
+		case found:
+		case res.err == nil:
+			out, found = res, true
+		case completed == 0:
+			out = res
+		}
+
+		if completed++; completed >= min {
+			break
+		}
+	}
+	cancel()
+	drain(f, results, nil)
+	return out.item, out.err
 }
 
-// ParallelInt runs the given functions in parallel.
+// ParallelFold runs the given functions in parallel, folding their successful results into an accumulator as they arrive.
 //
-// It collects all the errors and results (regardless if there were errors or not). To obtain
+// Starting with initial, combine is called with the accumulator and every successful result in the
+// order the results are received. combine is only ever called by the calling goroutine, so it does
+// not need to be safe for concurrent use. It collects all the errors in the returned error. To obtain
 // the multiple errors, use the `Errors` function.
-func (f *Flow) ParallelBool(ctx context.Context, fns ...BoolFunc) ([]bool, error) {
+func ParallelFold[T, Acc any](ctx context.Context, f *Flow, initial Acc, combine func(Acc, T) Acc, fns ...func(context.Context) (T, error)) (Acc, error) {
 	if len(fns) == 0 {
-		return nil, nil
+		return initial, nil
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return initial, err
 	}
 
-	c := make(chan boolResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		c <- boolResult{item, err}
-	}, func() { close(c) })
+	results := make(chan result[T], f.resultBufferFor(len(fns)))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- result[T]{i, item, err}
+		}
+	}, func() { close(results) })
 
 	var (
-		out  []bool
+		acc  = initial
 		errs multiError
 	)
-	for res := range c {
+	for res := range results {
 		if res.err != nil {
-			errs = append(errs, res.err)
+			errs = append(errs, indexedError{res.index, res.err})
 			continue
 		}
-		out = append(out, res.item)
+		acc = combine(acc, res.item)
 	}
-	return out, errs.ErrorOrNil()
+	return acc, f.aggregate(errs)
 }
 
-// ParallelBoolCancelOnError runs the given functions in parallel, cancelling all if one fails.
+// DetailedResult describes the execution of a single function of ParallelDetailed.
+type DetailedResult[T any] struct {
+	// Index is the index of the function.
+	Index int
+	// Value is the result of the function.
+	Value T
+	// Err is the error returned by the function.
+	Err error
+	// Duration is the time the function took to complete.
+	Duration time.Duration
+}
+
+// ParallelDetailed runs the given functions in parallel, returning the result, error and duration of each of them.
 //
-// It collects all the errors and results (regardless if there were errors or not). To obtain
-// the multiple errors, use the `Errors` function.
-func (f *Flow) ParallelBoolCancelOnError(ctx context.Context, fns ...BoolFunc) ([]bool, error) {
+// The i-th result describes the execution of the i-th function; the results of nil functions only
+// hold their index. If the Flow rejects the execution, e.g. because its maximum fan-out is exceeded,
+// no function is run and every result carries that error.
+func ParallelDetailed[T any](ctx context.Context, f *Flow, fns ...func(context.Context) (T, error)) []DetailedResult[T] {
 	if len(fns) == 0 {
-		return nil, nil
+		return nil
+	}
+
+	var (
+		out  = make([]DetailedResult[T], len(fns))
+		done = make(chan struct{})
+	)
+	if err := f.check(ctx, len(fns)); err != nil {
+		for i := range out {
+			out[i] = DetailedResult[T]{Index: i, Err: err}
+		}
+		return out
+	}
+
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		out[i].Index = i
+		if fn := fns[i]; fn != nil {
+			start := time.Now()
+			out[i].Value, out[i].Err = fn(ctx)
+			out[i].Duration = time.Since(start)
+		}
+	}, func() { close(done) })
+
+	<-done
+	return out
+}
+
+// WaitForAny polls all checks in parallel every interval and returns the index of the first that reports true.
+//
+// Each check is polled independently until it reports true or the context expires; failed polls
+// count as false. Once a check reported true, the polling of the others is cancelled. If the
+// context expires before, -1 is returned together with the context error.
+func (f *Flow) WaitForAny(ctx context.Context, interval time.Duration, checks ...BoolFunc) (int, error) {
+	if len(checks) == 0 {
+		return -1, nil
+	}
+	if err := f.check(ctx, len(checks)); err != nil {
+		return -1, err
 	}
 
+	parent := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	c := make(chan boolResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		c <- boolResult{item, err}
-	}, func() { close(c) })
+	healthy := make(chan int, len(checks))
+	f.runAll(ctx, len(checks), func(ctx context.Context, i int) {
+		check := checks[i]
+		if check == nil {
+			return
+		}
 
-	var (
-		out  []bool
-		errs multiError
-	)
-	for res := range c {
-		if res.err != nil {
-			cancel()
-			errs = append(errs, res.err)
-			continue
+		for {
+			if ok, err := check(ctx); ok && err == nil {
+				healthy <- i
+				return
+			}
+
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
 		}
-		out = append(out, res.item)
+	}, func() { close(healthy) })
+
+	i, ok := <-healthy
+	cancel()
+	for range healthy {
 	}
-	return out, errs.ErrorOrNil()
+
+	if !ok {
+		return -1, parent.Err()
+	}
+	return i, nil
 }
 
-// RaceBool runs all functions in parallel and returns the results of the first that completes.
+// VoteBool runs all functions in parallel and returns true once at least threshold of them returned true.
 //
-// Completion means a function either errors or succeeds.
-// The result of the succeeded function is returned, the other results are
-// discarded.
-func (f *Flow) RaceBool(ctx context.Context, fns ...BoolFunc) (bool, error) {
-	if len(fns) == 0 {
-		return false, nil
+// Once the threshold is reached, or too many functions returned false or failed to still reach it,
+// the remaining functions are cancelled and their results are discarded. In the latter case false
+// is returned together with the errors that occurred so far. To obtain the multiple errors, use
+// the `Errors` function. A threshold that is not positive is always reached. If fewer than
+// threshold functions are given, ErrQuorumUnreachable is returned without running any of them.
+func (f *Flow) VoteBool(ctx context.Context, threshold int, fns ...BoolFunc) (bool, error) {
+	if threshold <= 0 {
+		return true, nil
+	}
+
+	if err := f.check(ctx, len(fns)); err != nil {
+		return false, err
+	}
+
+	var n int
+	for _, fn := range fns {
+		if fn != nil {
+			n++
+		}
+	}
+	if threshold > n {
+		return false, ErrQuorumUnreachable
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	results := make(chan boolResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		results <- boolResult{item, err}
+	results := make(chan boolResult, len(fns))
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		if fn := fns[i]; fn != nil {
+			item, err := fn(ctx)
+			results <- boolResult{i, item, err}
+		}
 	}, func() { close(results) })
 
-	res := <-results
+	var (
+		yes, no int
+		errs    multiError
+	)
+	for res := range results {
+		switch {
+		case res.err != nil:
+			errs = append(errs, indexedError{res.index, res.err})
+			no++
+		case res.item:
+			yes++
+		default:
+			no++
+		}
+
+		if yes >= threshold || no > n-threshold {
+			break
+		}
+	}
 	cancel()
-	for range results {
+	drain(f, results, nil)
+
+	if yes >= threshold {
+		return true, nil
 	}
-	return res.item, res.err
+	return false, f.aggregate(errs)
 }
 
-// RaceCond runs all functions in parallel and returns the result of the first function that completes with an
-// error or with a truthy result.
-func (f *Flow) RaceCond(ctx context.Context, fns ...BoolFunc) (bool, error) {
+// SearchBool runs the functions in order with at most concurrency of them running at the same time,
+// returning the result of the first function that completes with an error or with a truthy result.
+//
+// Unlike RaceCond, functions are launched one after another as the previous ones complete. Once a
+// qualifying result is received, no further functions are launched and the running ones are cancelled.
+// If the context expires before a result qualified, the search is aborted and the context error is
+// returned. If concurrency is not positive, all functions may run at the same time.
+func (f *Flow) SearchBool(ctx context.Context, concurrency int, fns ...BoolFunc) (bool, error) {
 	if len(fns) == 0 {
 		return false, nil
 	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		return false, err
+	}
+	if concurrency <= 0 || concurrency > len(fns) {
+		concurrency = len(fns)
+	}
 
+	parent := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	results := make(chan boolResult)
-	f.runAll(len(fns), func(i int) {
-		item, err := fns[i](ctx)
-		results <- boolResult{item, err}
-	}, func() { close(results) })
+	var (
+		executor = f.executorFor(ctx)
+		sem      = newSemaphore(concurrency)
+		results  = make(chan boolResult)
+	)
+	go func() {
+		var wg sync.WaitGroup
+		defer close(results)
+		defer wg.Wait()
+
+		for i, fn := range fns {
+			if fn == nil {
+				continue
+			}
+			if err := sem.acquire(ctx); err != nil {
+				return
+			}
+
+			i, fn, done := i, fn, f.track()
+			wg.Add(1)
+			executor.Submit(func() {
+				defer wg.Done()
+				defer done()
+				item, err := fn(f.withBatchInfo(ctx, i, len(fns)))
+				results <- boolResult{i, item, err}
+			})
+		}
+	}()
 
-	var out boolResult
+	var (
+		out     boolResult
+		decided bool
+	)
 	for res := range results {
 		if res.err != nil || res.item {
 			cancel()
-			out = res
+			out, decided = res, true
 			break
 		}
+		sem.release()
 	}
-	for range results {
+	drain(f, results, nil)
+	if !decided {
+		// The search was aborted if the parent context expired before all functions were launched.
+		return false, parent.Err()
 	}
 	return out.item, out.err
 }
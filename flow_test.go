@@ -2,8 +2,14 @@ package flow_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	. "github.com/adracus/flow"
 	"github.com/adracus/flow/mock"
@@ -21,6 +27,8 @@ func mkError(id int) error {
 	return fmt.Errorf("error %d", id)
 }
 
+var cancellation = &CancellationError{Err: context.Canceled}
+
 func waitForContextToErrorAndReturnError(ctx context.Context) error {
 	Eventually(ctx.Err).Should(HaveOccurred())
 	return ctx.Err()
@@ -41,6 +49,19 @@ func waitForContextToErrorAndReturnBoolError(ctx context.Context) (bool, error)
 	return false, ctx.Err()
 }
 
+type countingCloser struct {
+	closed int32
+}
+
+func (c *countingCloser) Close() error {
+	atomic.AddInt32(&c.closed, 1)
+	return nil
+}
+
+func (c *countingCloser) Closed() int32 {
+	return atomic.LoadInt32(&c.closed)
+}
+
 var _ = Describe("Flow", func() {
 	var ctrl *gomock.Controller
 	BeforeEach(func() {
@@ -73,82 +94,104 @@ var _ = Describe("Flow", func() {
 		})
 	})
 
-	Describe("ParallelCancelOnError", func() {
-		It("should run the functions and cancel all if one of them errors", func() {
-			var (
-				err1 = mkError(1)
-				f1   = mock.NewMockFunc(ctrl)
-				f2   = mock.NewMockFunc(ctrl)
-				f3   = mock.NewMockFunc(ctrl)
+	Describe("WithErrorAggregator", func() {
+		var (
+			err1, err2, err3 error
+			fns              []Func
+		)
+		BeforeEach(func() {
+			err1, err2, err3 = mkError(1), mkError(2), mkError(3)
+			fns = []Func{
+				func(context.Context) error { return err1 },
+				func(context.Context) error { return err2 },
+				func(context.Context) error { return err3 },
+			}
+		})
 
-				ctx = context.TODO()
-			)
+		It("should keep all errors with AllErrors", func() {
+			err := New(SyncExecutor, WithErrorAggregator(AllErrors)).Parallel(context.TODO(), fns...)
+			Expect(Errors(err)).To(Equal([]error{err1, err2, err3}))
+		})
 
-			f1.EXPECT().Call(gomock.Any()).Return(err1)
-			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
-			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+		It("should keep the first error with FirstError", func() {
+			err := New(SyncExecutor, WithErrorAggregator(FirstError)).Parallel(context.TODO(), fns...)
+			Expect(err).To(BeIdenticalTo(err1))
+		})
 
-			err := ParallelCancelOnError(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).To(HaveOccurred())
-			Expect(Errors(err)).To(ConsistOf(err1, context.Canceled, context.Canceled))
+		It("should keep the last error with LastError", func() {
+			err := New(SyncExecutor, WithErrorAggregator(LastError)).Parallel(context.TODO(), fns...)
+			Expect(err).To(BeIdenticalTo(err3))
 		})
 
-		It("should run the functions and return no error if all succeed", func() {
+		It("should not call the aggregator if there are no errors", func() {
+			f := New(SyncExecutor, WithErrorAggregator(func([]error) error { return mkError(0) }))
+			Expect(f.Parallel(context.TODO(), func(context.Context) error { return nil })).To(Succeed())
+		})
+	})
+
+	Describe("ParallelOn", func() {
+		It("should submit to the given executor instead of the flow's executor", func() {
 			var (
-				f1 = mock.NewMockFunc(ctrl)
-				f2 = mock.NewMockFunc(ctrl)
-				f3 = mock.NewMockFunc(ctrl)
+				flowEx = mock.NewMockExecutor(ctrl)
+				callEx = mock.NewMockExecutor(ctrl)
+				f1     = mock.NewMockFunc(ctrl)
+				f2     = mock.NewMockFunc(ctrl)
 
 				ctx = context.TODO()
 			)
-			f1.EXPECT().Call(gomock.Any())
-			f2.EXPECT().Call(gomock.Any())
-			f3.EXPECT().Call(gomock.Any())
 
-			Expect(ParallelCancelOnError(ctx, f1.Call, f2.Call, f3.Call)).NotTo(HaveOccurred())
+			callEx.EXPECT().Submit(gomock.Any()).Times(2).Do(func(f func()) { go f() })
+			f1.EXPECT().Call(ctx)
+			f2.EXPECT().Call(ctx)
+
+			Expect(New(flowEx).ParallelOn(ctx, callEx, f1.Call, f2.Call)).To(Succeed())
 		})
 	})
 
-	Describe("Sequence", func() {
-		It("should run the functions one after another", func() {
+	Describe("ErrorsByIndex", func() {
+		It("should return the errors by the index of the failed function", func() {
 			var (
-				f1  = mock.NewMockFunc(ctrl)
-				f2  = mock.NewMockFunc(ctrl)
-				f3  = mock.NewMockFunc(ctrl)
+				err1 = mkError(1)
+				err3 = mkError(3)
+
+				f0 = mock.NewMockFunc(ctrl)
+				f1 = mock.NewMockFunc(ctrl)
+				f3 = mock.NewMockFunc(ctrl)
+
 				ctx = context.TODO()
 			)
 
-			gomock.InOrder(
-				f1.EXPECT().Call(ctx),
-				f2.EXPECT().Call(ctx),
-				f3.EXPECT().Call(ctx),
-			)
+			f0.EXPECT().Call(ctx)
+			f1.EXPECT().Call(ctx).Return(err1)
+			f3.EXPECT().Call(ctx).Return(err3)
 
-			Expect(Sequence(ctx, f1.Call, f2.Call, f3.Call)).To(Succeed())
+			err := Parallel(ctx, f0.Call, f1.Call, nil, f3.Call)
+			Expect(ErrorsByIndex(err)).To(Equal(map[int]error{1: err1, 3: err3}))
 		})
 
-		It("should exit with the first error encountered", func() {
+		It("should return the errors by index for the typed families", func() {
 			var (
-				err2 = mkError(2)
-				f1   = mock.NewMockFunc(ctrl)
-				f2   = mock.NewMockFunc(ctrl)
-				f3   = mock.NewMockFunc(ctrl)
-				ctx  = context.TODO()
-			)
+				err0 = mkError(0)
+				f0   = mock.NewMockIntFunc(ctrl)
+				f1   = mock.NewMockIntFunc(ctrl)
 
-			gomock.InOrder(
-				f1.EXPECT().Call(ctx),
-				f2.EXPECT().Call(ctx).Return(err2),
+				ctx = context.TODO()
 			)
 
-			err := Sequence(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).To(HaveOccurred())
-			Expect(err).To(BeIdenticalTo(err2))
+			f0.EXPECT().Call(ctx).Return(0, err0)
+			f1.EXPECT().Call(ctx).Return(1, nil)
+
+			_, err := ParallelInt(ctx, f0.Call, f1.Call)
+			Expect(ErrorsByIndex(err)).To(Equal(map[int]error{0: err0}))
+		})
+
+		It("should return nil for errors not produced by a parallel execution", func() {
+			Expect(ErrorsByIndex(mkError(1))).To(BeNil())
 		})
 	})
 
-	Describe("Race", func() {
-		It("should return the result of the first function and cancel the others", func() {
+	Describe("ParallelCancelOnError", func() {
+		It("should run the functions and cancel all if one of them errors", func() {
 			var (
 				err1 = mkError(1)
 				f1   = mock.NewMockFunc(ctrl)
@@ -162,215 +205,1744 @@ var _ = Describe("Flow", func() {
 			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
 			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
 
-			err := Race(ctx, f1.Call, f2.Call, f3.Call)
+			err := ParallelCancelOnError(ctx, f1.Call, f2.Call, f3.Call)
 			Expect(err).To(HaveOccurred())
-			Expect(err).To(BeIdenticalTo(err1))
+			Expect(Errors(err)).To(ConsistOf(err1, cancellation, cancellation))
 		})
-	})
 
-	Describe("ParallelString", func() {
-		It("should run all computations, returning all errors and results", func() {
+		It("should mark the errors caused by its cancellation", func() {
 			var (
 				err1 = mkError(1)
-				f1   = mock.NewMockStringFunc(ctrl)
-				f2   = mock.NewMockStringFunc(ctrl)
-				f3   = mock.NewMockStringFunc(ctrl)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
 
 				ctx = context.TODO()
 			)
 
-			f1.EXPECT().Call(gomock.Any()).Return("", err1)
-			f2.EXPECT().Call(gomock.Any()).Return("foo", nil)
-			f3.EXPECT().Call(gomock.Any()).Return("bar", nil)
+			f1.EXPECT().Call(gomock.Any()).Return(err1)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
 
-			res, err := ParallelString(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).To(HaveOccurred())
-			Expect(Errors(err)).To(ConsistOf(err1))
-			Expect(res).To(ConsistOf("foo", "bar"))
+			var genuine, cancelled []error
+			for _, err := range Errors(ParallelCancelOnError(ctx, f1.Call, f2.Call)) {
+				var cerr *CancellationError
+				if errors.As(err, &cerr) {
+					cancelled = append(cancelled, err)
+					continue
+				}
+				genuine = append(genuine, err)
+			}
+			Expect(genuine).To(ConsistOf(err1))
+			Expect(cancelled).To(HaveLen(1))
+			Expect(errors.Is(cancelled[0], context.Canceled)).To(BeTrue())
 		})
-	})
 
-	Describe("ParallelStringCancelOnError", func() {
-		It("should run all computations, cancelling them when an error occurs", func() {
+		It("should not mark cancellations of the parent context", func() {
 			var (
-				err1 = mkError(1)
-				f1   = mock.NewMockStringFunc(ctrl)
-				f2   = mock.NewMockStringFunc(ctrl)
-				f3   = mock.NewMockStringFunc(ctrl)
-
-				ctx = context.TODO()
+				f1          = mock.NewMockFunc(ctrl)
+				ctx, cancel = context.WithCancel(context.Background())
 			)
+			cancel()
 
-			f1.EXPECT().Call(gomock.Any()).Return("", err1)
-			f2.EXPECT().Call(gomock.Any()).Return("foo", nil)
-			f3.EXPECT().Call(gomock.Any()).Return("bar", nil)
+			f1.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
 
-			res, err := ParallelStringCancelOnError(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).To(HaveOccurred())
-			Expect(Errors(err)).To(ConsistOf(err1))
-			Expect(res).To(ConsistOf("foo", "bar"))
+			err := ParallelCancelOnError(ctx, f1.Call)
+			Expect(Errors(err)).To(ConsistOf(context.Canceled))
 		})
-	})
 
-	Describe("RaceString", func() {
-		It("should run all computations, returning as soon as one of them finishes", func() {
+		It("should run the functions and return no error if all succeed", func() {
 			var (
-				f1 = mock.NewMockStringFunc(ctrl)
-				f2 = mock.NewMockStringFunc(ctrl)
-				f3 = mock.NewMockStringFunc(ctrl)
+				f1 = mock.NewMockFunc(ctrl)
+				f2 = mock.NewMockFunc(ctrl)
+				f3 = mock.NewMockFunc(ctrl)
 
 				ctx = context.TODO()
 			)
+			f1.EXPECT().Call(gomock.Any())
+			f2.EXPECT().Call(gomock.Any())
+			f3.EXPECT().Call(gomock.Any())
 
-			f1.EXPECT().Call(gomock.Any()).Return("foo", nil)
-			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnStringError)
-			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnStringError)
-
-			res, err := RaceString(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(res).To(Equal("foo"))
+			Expect(ParallelCancelOnError(ctx, f1.Call, f2.Call, f3.Call)).NotTo(HaveOccurred())
 		})
-	})
 
-	Describe("ParallelInt", func() {
-		It("should run all computations, returning all errors and results", func() {
+		It("should promptly cancel the siblings on the first error", func(done Done) {
+			defer close(done)
 			var (
-				err1 = mkError(1)
-				f1   = mock.NewMockIntFunc(ctrl)
-				f2   = mock.NewMockIntFunc(ctrl)
-				f3   = mock.NewMockIntFunc(ctrl)
-
-				ctx = context.TODO()
+				err1      = mkError(1)
+				cancelled = make(chan struct{}, 2)
+				sibling   = func(ctx context.Context) error {
+					<-ctx.Done()
+					cancelled <- struct{}{}
+					return ctx.Err()
+				}
 			)
 
-			f1.EXPECT().Call(gomock.Any()).Return(0, err1)
-			f2.EXPECT().Call(gomock.Any()).Return(1, nil)
-			f3.EXPECT().Call(gomock.Any()).Return(2, nil)
+			err := ParallelCancelOnError(context.TODO(), func(context.Context) error { return err1 }, sibling, sibling)
+			Expect(Errors(err)).To(ConsistOf(err1, cancellation, cancellation))
+			Expect(cancelled).To(HaveLen(2))
+		})
 
-			res, err := ParallelInt(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).To(HaveOccurred())
-			Expect(Errors(err)).To(ConsistOf(err1))
-			Expect(res).To(ConsistOf(1, 2))
+		It("should not leak goroutines", func() {
+			before := runtime.NumGoroutine()
+			for i := 0; i < 10; i++ {
+				_ = ParallelCancelOnError(context.TODO(),
+					func(context.Context) error { return mkError(1) },
+					waitForContextToErrorAndReturnError,
+					waitForContextToErrorAndReturnError,
+				)
+			}
+			Eventually(runtime.NumGoroutine).Should(BeNumerically("<=", before))
 		})
 	})
 
-	Describe("ParallelIntCancelOnError", func() {
-		It("should run all computations, cancelling them when an error occurs", func() {
+	Describe("Dedup", func() {
+		It("should collapse identical errors and count their occurrences", func() {
 			var (
-				err1 = mkError(1)
-				f1   = mock.NewMockIntFunc(ctrl)
-				f2   = mock.NewMockIntFunc(ctrl)
-				f3   = mock.NewMockIntFunc(ctrl)
-
-				ctx = context.TODO()
+				errDB = errors.New("db unavailable")
+				err1  = mkError(1)
+				err2  = mkError(2)
+				fns   = []Func{
+					func(context.Context) error { return errDB },
+					func(context.Context) error { return err1 },
+					func(context.Context) error { return errDB },
+					func(context.Context) error { return err2 },
+					func(context.Context) error { return errDB },
+				}
 			)
 
-			f1.EXPECT().Call(gomock.Any()).Return(0, err1)
-			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
-			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+			errs := Errors(Dedup(New(SyncExecutor).Parallel(context.TODO(), fns...)))
+			Expect(errs).To(HaveLen(3))
+			Expect(errs[0]).To(MatchError("db unavailable (x3)"))
+			Expect(errors.Is(errs[0], errDB)).To(BeTrue())
+			Expect(errs[1:]).To(Equal([]error{err1, err2}))
+		})
 
-			res, err := ParallelIntCancelOnError(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).To(HaveOccurred())
-			Expect(Errors(err)).To(ConsistOf(err1, context.Canceled, context.Canceled))
-			Expect(res).To(BeEmpty())
+		It("should return other errors as is", func() {
+			err1 := mkError(1)
+			Expect(Dedup(err1)).To(BeIdenticalTo(err1))
+			Expect(Dedup(nil)).To(BeNil())
 		})
 	})
 
-	Describe("RaceInt", func() {
-		It("should run all computations, returning as soon as one of them finishes", func() {
-			var (
-				f1 = mock.NewMockIntFunc(ctrl)
-				f2 = mock.NewMockIntFunc(ctrl)
-				f3 = mock.NewMockIntFunc(ctrl)
+	Describe("WithOrderedResults", func() {
+		var fns []StringFunc
+		BeforeEach(func() {
+			fns = nil
+			for i := 0; i < 5; i++ {
+				i := i
+				fns = append(fns, func(context.Context) (string, error) {
+					time.Sleep(time.Duration(5-i) * 10 * time.Millisecond)
+					if i == 2 {
+						return "", mkError(i)
+					}
+					return fmt.Sprint(i), nil
+				})
+			}
+		})
 
-				ctx = context.TODO()
-			)
+		It("should return the results in the order of the functions", func() {
+			res, err := New(UnlimitedExecutor, WithOrderedResults(true)).ParallelString(context.TODO(), fns...)
+			Expect(Errors(err)).To(ConsistOf(mkError(2)))
+			Expect(res).To(Equal([]string{"0", "1", "", "3", "4"}))
+		})
 
-			f1.EXPECT().Call(gomock.Any()).Return(1, nil)
-			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
-			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+		It("should return the results in the order of completion by default", func() {
+			res, err := New(reverseExecutor(5)).ParallelString(context.TODO(), fns...)
+			Expect(Errors(err)).To(ConsistOf(mkError(2)))
+			Expect(res).To(Equal([]string{"4", "3", "1", "0"}))
+		})
 
-			res, err := RaceInt(ctx, f1.Call, f2.Call, f3.Call)
+		It("should order the results of the other types as well", func() {
+			f := New(UnlimitedExecutor, WithOrderedResults(true))
+
+			ints, err := f.ParallelIntCancelOnError(context.TODO(),
+				func(context.Context) (int, error) { time.Sleep(10 * time.Millisecond); return 1, nil },
+				func(context.Context) (int, error) { return 2, nil },
+			)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(res).To(Equal(1))
+			Expect(ints).To(Equal([]int{1, 2}))
+
+			bools, err := f.ParallelBool(context.TODO(),
+				func(context.Context) (bool, error) { time.Sleep(10 * time.Millisecond); return true, nil },
+				func(context.Context) (bool, error) { return false, nil },
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bools).To(Equal([]bool{true, false}))
 		})
 	})
 
-	Describe("ParallelBool", func() {
-		It("should run all computations, returning all errors and results", func() {
-			var (
-				err1 = mkError(1)
-				f1   = mock.NewMockBoolFunc(ctrl)
-				f2   = mock.NewMockBoolFunc(ctrl)
-				f3   = mock.NewMockBoolFunc(ctrl)
-
-				ctx = context.TODO()
-			)
+	Describe("WithIgnoreContextErrors", func() {
+		var f *Flow
+		BeforeEach(func() {
+			f = New(SyncExecutor, WithIgnoreContextErrors())
+		})
 
-			f1.EXPECT().Call(gomock.Any()).Return(false, err1)
-			f2.EXPECT().Call(gomock.Any()).Return(false, nil)
-			f3.EXPECT().Call(gomock.Any()).Return(true, nil)
+		It("should treat functions failing with a context error as successful", func() {
+			Expect(f.Parallel(context.TODO(),
+				func(context.Context) error { return context.Canceled },
+				func(context.Context) error { return fmt.Errorf("cleanup: %w", context.DeadlineExceeded) },
+				func(context.Context) error { return nil },
+			)).To(Succeed())
 
-			res, err := ParallelBool(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).To(HaveOccurred())
-			Expect(Errors(err)).To(ConsistOf(err1))
-			Expect(res).To(ConsistOf(false, true))
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(f.Parallel(ctx, waitForContextToErrorAndReturnError, waitForContextToErrorAndReturnError)).To(Succeed())
 		})
-	})
 
-	Describe("ParallelBoolCancelOnError", func() {
-		It("should run all computations, cancelling them when an error occurs", func() {
-			var (
-				err1 = mkError(1)
-				f1   = mock.NewMockBoolFunc(ctrl)
-				f2   = mock.NewMockBoolFunc(ctrl)
-				f3   = mock.NewMockBoolFunc(ctrl)
+		It("should still collect the other errors", func() {
+			err := f.Parallel(context.TODO(),
+				func(context.Context) error { return context.Canceled },
+				func(context.Context) error { return mkError(1) },
+			)
+			Expect(Errors(err)).To(ConsistOf(mkError(1)))
+		})
 
-				ctx = context.TODO()
+		It("should ignore context errors in the typed variants", func() {
+			strs, err := f.ParallelString(context.TODO(),
+				func(context.Context) (string, error) { return "", context.Canceled },
+				func(context.Context) (string, error) { return "a", nil },
 			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strs).To(ConsistOf("", "a"))
 
-			f1.EXPECT().Call(gomock.Any()).Return(false, err1)
-			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
-			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+			ints, err := f.ParallelInt(context.TODO(), func(context.Context) (int, error) { return 0, context.Canceled })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ints).To(Equal([]int{0}))
 
-			res, err := ParallelBoolCancelOnError(ctx, f1.Call, f2.Call, f3.Call)
-			Expect(err).To(HaveOccurred())
-			Expect(Errors(err)).To(ConsistOf(err1, context.Canceled, context.Canceled))
-			Expect(res).To(BeEmpty())
+			bools, err := f.ParallelBool(context.TODO(), func(context.Context) (bool, error) { return false, context.DeadlineExceeded })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bools).To(Equal([]bool{false}))
+		})
+
+		It("should report context errors by default", func() {
+			err := New(SyncExecutor).Parallel(context.TODO(), func(context.Context) error { return context.Canceled })
+			Expect(Errors(err)).To(ConsistOf(context.Canceled))
 		})
 	})
 
-	Describe("RaceBool", func() {
-		It("should run all computations, returning as soon as one of them finishes", func() {
+	Describe("Wrap", func() {
+		It("should adapt functions without context for Parallel", func() {
 			var (
-				f1 = mock.NewMockBoolFunc(ctrl)
-				f2 = mock.NewMockBoolFunc(ctrl)
-				f3 = mock.NewMockBoolFunc(ctrl)
+				err1  = mkError(1)
+				calls int32
+			)
 
-				ctx = context.TODO()
+			err := Parallel(context.TODO(),
+				Wrap(func() error { atomic.AddInt32(&calls, 1); return nil }),
+				Wrap(func() error { atomic.AddInt32(&calls, 1); return err1 }),
 			)
+			Expect(Errors(err)).To(ConsistOf(err1))
+			Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+		})
 
-			f1.EXPECT().Call(gomock.Any()).Return(true, nil)
-			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
-			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+		It("should adapt functions without context for Sequence", func() {
+			var order []int
 
-			res, err := RaceBool(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(Sequence(context.TODO(),
+				Wrap(func() error { order = append(order, 1); return nil }),
+				Wrap(func() error { order = append(order, 2); return nil }),
+			)).To(Succeed())
+			Expect(order).To(Equal([]int{1, 2}))
+		})
+
+		It("should adapt typed functions without context", func() {
+			s, err := ParallelString(context.TODO(), WrapString(func() (string, error) { return "a", nil }))
 			Expect(err).NotTo(HaveOccurred())
-			Expect(res).To(BeTrue())
+			Expect(s).To(Equal([]string{"a"}))
+
+			i, err := ParallelInt(context.TODO(), WrapInt(func() (int, error) { return 1, nil }))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(i).To(Equal([]int{1}))
+
+			b, err := ParallelBool(context.TODO(), WrapBool(func() (bool, error) { return true, nil }))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b).To(Equal([]bool{true}))
 		})
 	})
 
-	Describe("RaceCond", func() {
-		It("should run all computations, returning as soon as one of them returns no error and true", func() {
-			var (
-				f1 = mock.NewMockBoolFunc(ctrl)
-				f2 = mock.NewMockBoolFunc(ctrl)
-				f3 = mock.NewMockBoolFunc(ctrl)
+	Describe("Adapters", func() {
+		var (
+			err1 = mkError(1)
+			ctx  = context.TODO()
+		)
 
-				ctx = context.TODO()
-			)
+		It("should discard the result of a StringFunc", func() {
+			var ran bool
+			fn := StringFunc(func(context.Context) (string, error) { ran = true; return "a", nil })
 
-			f1.EXPECT().Call(gomock.Any()).Return(true, nil)
+			Expect(Sequence(ctx, fn.Discard())).To(Succeed())
+			Expect(ran).To(BeTrue())
+			Expect(StringFunc(func(context.Context) (string, error) { return "", err1 }).Discard()(ctx)).To(BeIdenticalTo(err1))
+		})
+
+		It("should map the result of an IntFunc", func() {
+			double := func(n int) int { return 2 * n }
+
+			n, err := IntFunc(func(context.Context) (int, error) { return 2, nil }).Map(double)(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(4))
+
+			n, err = IntFunc(func(context.Context) (int, error) { return 2, err1 }).Map(double)(ctx)
+			Expect(err).To(BeIdenticalTo(err1))
+			Expect(n).To(BeZero())
+		})
+
+		It("should convert the result of an IntFunc to a bool", func() {
+			positive := func(n int) bool { return n > 0 }
+
+			ok, err := IntFunc(func(context.Context) (int, error) { return 1, nil }).ToBool(positive)(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			ok, err = IntFunc(func(context.Context) (int, error) { return 1, err1 }).ToBool(positive)(ctx)
+			Expect(err).To(BeIdenticalTo(err1))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should negate the result of a BoolFunc", func() {
+			ok, err := BoolFunc(func(context.Context) (bool, error) { return false, nil }).Negate()(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			ok, err = BoolFunc(func(context.Context) (bool, error) { return false, err1 }).Negate()(ctx)
+			Expect(err).To(BeIdenticalTo(err1))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("SequenceBudget", func() {
+		It("should cut off a slow step at its share and still run the later steps", func() {
+			var (
+				slices []time.Duration
+				step   = func(slow bool) Func {
+					return func(ctx context.Context) error {
+						start := time.Now()
+						if slow {
+							<-ctx.Done()
+						}
+						slices = append(slices, time.Since(start))
+						return nil
+					}
+				}
+			)
+
+			Expect(SequenceBudget(context.TODO(), 300*time.Millisecond, step(true), step(false), step(true))).To(Succeed())
+			Expect(slices).To(HaveLen(3))
+			Expect(slices[0]).To(BeNumerically("~", 100*time.Millisecond, 50*time.Millisecond))
+			Expect(slices[2]).To(BeNumerically("~", 200*time.Millisecond, 50*time.Millisecond))
+		})
+
+		It("should exit with the first error encountered", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(err1)
+
+			Expect(SequenceBudget(context.TODO(), time.Second, f1.Call, f2.Call)).To(Equal(err1))
+		})
+	})
+
+	Describe("SequenceRecover", func() {
+		It("should convert a panic into an error and not run the later functions", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+				f3   = mock.NewMockFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(ctx)
+			f2.EXPECT().Call(ctx).Do(func(context.Context) { panic(err1) })
+
+			err := SequenceRecover(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(MatchError("panic in step 1: error 1"))
+			Expect(errors.Is(err, err1)).To(BeTrue())
+
+			var perr *PanicError
+			Expect(errors.As(err, &perr)).To(BeTrue())
+			Expect(perr.Value).To(BeIdenticalTo(err1))
+			Expect(perr.Stack).NotTo(BeEmpty())
+		})
+	})
+
+	Describe("SequenceFunc", func() {
+		It("should run the sequence on every attempt of a retry", func() {
+			var (
+				f1  = mock.NewMockFunc(ctrl)
+				f2  = mock.NewMockFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			gomock.InOrder(
+				f1.EXPECT().Call(ctx),
+				f2.EXPECT().Call(ctx).Return(mkError(2)),
+				f1.EXPECT().Call(ctx),
+				f2.EXPECT().Call(ctx),
+			)
+
+			Expect(Retry(ctx, SequenceFunc(f1.Call, f2.Call), 3, ConstantBackoff(0))).To(Succeed())
+		})
+
+		It("should run the sequences in parallel", func() {
+			var (
+				f1  = mock.NewMockFunc(ctrl)
+				f2  = mock.NewMockFunc(ctrl)
+				f3  = mock.NewMockFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(ctx).Times(2)
+			f2.EXPECT().Call(ctx).Times(2)
+			f3.EXPECT().Call(ctx)
+
+			Expect(Parallel(ctx, SequenceFunc(f1.Call, f2.Call), SequenceFunc(f1.Call, f2.Call), f3.Call)).To(Succeed())
+		})
+	})
+
+	Describe("BatchInfo", func() {
+		It("should provide the index and total to the functions of Parallel if enabled", func() {
+			var (
+				fns     = make([]Func, 5)
+				indices = make([]int, 5)
+				totals  = make([]int, 5)
+			)
+			for i := range fns {
+				i := i
+				fns[i] = func(ctx context.Context) error {
+					index, total, ok := BatchInfo(ctx)
+					if !ok {
+						return mkError(i)
+					}
+					indices[i], totals[i] = index, total
+					return nil
+				}
+			}
+
+			Expect(New(UnlimitedExecutor, WithBatchInfo()).Parallel(context.TODO(), fns...)).To(Succeed())
+			Expect(indices).To(Equal([]int{0, 1, 2, 3, 4}))
+			Expect(totals).To(Equal([]int{5, 5, 5, 5, 5}))
+		})
+
+		It("should provide the index and total to the functions of the other operations", func() {
+			var (
+				f   = New(UnlimitedExecutor, WithBatchInfo())
+				fns = make([]StringFunc, 3)
+			)
+			for i := range fns {
+				fns[i] = func(ctx context.Context) (string, error) {
+					index, total, ok := BatchInfo(ctx)
+					if !ok {
+						return "", errors.New("no batch info")
+					}
+					return fmt.Sprintf("%d/%d", index, total), nil
+				}
+			}
+
+			res, err := f.ParallelString(context.TODO(), fns...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(ConsistOf("0/3", "1/3", "2/3"))
+
+			out, err := MapN(context.TODO(), f, 2, []int{10, 20}, func(ctx context.Context, n int) (string, error) {
+				index, total, _ := BatchInfo(ctx)
+				return fmt.Sprintf("%d:%d/%d", n, index, total), nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(Equal([]string{"10:0/2", "20:1/2"}))
+		})
+
+		It("should report no information outside of a batch", func() {
+			_, _, ok := BatchInfo(context.TODO())
+			Expect(ok).To(BeFalse())
+
+			Expect(Parallel(context.TODO(), func(ctx context.Context) error {
+				_, _, ok = BatchInfo(ctx)
+				return nil
+			})).To(Succeed())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("ParallelFunc", func() {
+		It("should run the functions in parallel when invoked", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+				f3   = mock.NewMockFunc(ctrl)
+				ctx  = context.TODO()
+			)
+
+			f1.EXPECT().Call(ctx)
+			f2.EXPECT().Call(ctx).Return(err1)
+
+			err := Sequence(ctx, ParallelFunc(f1.Call, f2.Call), f3.Call)
+			Expect(Errors(err)).To(ConsistOf(err1))
+		})
+
+		It("should run the functions of every invocation", func() {
+			var (
+				f1  = mock.NewMockFunc(ctrl)
+				f2  = mock.NewMockFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(ctx).Times(2)
+			f2.EXPECT().Call(ctx).Times(2)
+
+			fn := ParallelFunc(f1.Call, f2.Call)
+			Expect(Sequence(ctx, fn, fn)).To(Succeed())
+		})
+	})
+
+	Describe("Sequence", func() {
+		It("should run the functions one after another", func() {
+			var (
+				f1  = mock.NewMockFunc(ctrl)
+				f2  = mock.NewMockFunc(ctrl)
+				f3  = mock.NewMockFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			gomock.InOrder(
+				f1.EXPECT().Call(ctx),
+				f2.EXPECT().Call(ctx),
+				f3.EXPECT().Call(ctx),
+			)
+
+			Expect(Sequence(ctx, f1.Call, f2.Call, f3.Call)).To(Succeed())
+		})
+
+		It("should exit with the first error encountered", func() {
+			var (
+				err2 = mkError(2)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+				f3   = mock.NewMockFunc(ctrl)
+				ctx  = context.TODO()
+			)
+
+			gomock.InOrder(
+				f1.EXPECT().Call(ctx),
+				f2.EXPECT().Call(ctx).Return(err2),
+			)
+
+			err := Sequence(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeIdenticalTo(err2))
+		})
+	})
+
+	Describe("ParallelFailFast", func() {
+		It("should return the first error without waiting for the other functions", func() {
+			var (
+				err1    = mkError(1)
+				f1      = mock.NewMockFunc(ctrl)
+				f2      = mock.NewMockFunc(ctrl)
+				release = make(chan struct{})
+				done    = make(chan struct{})
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(ctx).Return(err1)
+			f2.EXPECT().Call(ctx).DoAndReturn(func(context.Context) error {
+				defer close(done)
+				<-release
+				return nil
+			})
+
+			Expect(ParallelFailFast(ctx, f1.Call, f2.Call)).To(BeIdenticalTo(err1))
+			Consistently(done).ShouldNot(BeClosed())
+			close(release)
+			Eventually(done).Should(BeClosed())
+		})
+
+		It("should return nil if all functions succeed", func() {
+			var (
+				f1  = mock.NewMockFunc(ctrl)
+				f2  = mock.NewMockFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(ctx)
+			f2.EXPECT().Call(ctx)
+
+			Expect(ParallelFailFast(ctx, f1.Call, f2.Call)).To(Succeed())
+		})
+	})
+
+	Describe("ParallelBG", func() {
+		It("should pass the default context of the flow to the functions", func() {
+			type key struct{}
+			var (
+				ctx = context.WithValue(context.Background(), key{}, "value")
+				f   = New(UnlimitedExecutor).WithDefaultContext(ctx)
+				f1  = mock.NewMockFunc(ctrl)
+				f2  = mock.NewMockFunc(ctrl)
+			)
+
+			f1.EXPECT().Call(ctx)
+			f2.EXPECT().Call(ctx)
+
+			Expect(f.ParallelBG(f1.Call, f2.Call)).To(Succeed())
+		})
+
+		It("should fall back to the background context", func() {
+			f1 := mock.NewMockFunc(ctrl)
+			f1.EXPECT().Call(context.Background())
+
+			Expect(New(UnlimitedExecutor).ParallelBG(f1.Call)).To(Succeed())
+		})
+	})
+
+	Describe("HandleSignals", func() {
+		It("should cancel the context when calling the returned cancel", func() {
+			type key struct{}
+			f := New(UnlimitedExecutor).WithDefaultContext(context.WithValue(context.Background(), key{}, "value"))
+
+			ctx, cancel := f.HandleSignals(os.Interrupt)
+			Expect(ctx.Value(key{})).To(Equal("value"))
+			Expect(ctx.Err()).NotTo(HaveOccurred())
+
+			cancel()
+			Expect(ctx.Err()).To(MatchError(context.Canceled))
+			Expect(Errors(f.Parallel(ctx, waitForContextToErrorAndReturnError))).To(ConsistOf(context.Canceled))
+		})
+
+		It("should not be cancelled by the signals the runtime uses if no signals are given", func() {
+			ctx, cancel := New(UnlimitedExecutor).HandleSignals()
+			defer cancel()
+
+			// Busy goroutines make the runtime preempt them, which it does by sending SIGURG.
+			deadline := time.Now().Add(100 * time.Millisecond)
+			Expect(Parallel(context.TODO(),
+				func(context.Context) error {
+					for time.Now().Before(deadline) {
+					}
+					return nil
+				},
+				func(context.Context) error {
+					for time.Now().Before(deadline) {
+					}
+					return nil
+				},
+			)).To(Succeed())
+			Expect(ctx.Err()).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("ParallelUntilErrors", func() {
+		It("should cancel the remaining functions once the maximum number of errors is reached", func() {
+			var (
+				err1 = mkError(1)
+				err2 = mkError(2)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+				f3   = mock.NewMockFunc(ctrl)
+				f4   = mock.NewMockFunc(ctrl)
+				f5   = mock.NewMockFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(err1)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(func(context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				return err2
+			})
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+			f4.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+			f5.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+
+			err := ParallelUntilErrors(ctx, 2, f1.Call, f2.Call, f3.Call, f4.Call, f5.Call)
+			Expect(Errors(err)).To(ConsistOf(err1, err2, cancellation, cancellation, cancellation))
+		})
+
+		It("should not cancel the functions while fewer errors occurred", func() {
+			var (
+				err1 = mkError(1)
+				ctx  = context.TODO()
+			)
+
+			err := ParallelUntilErrors(ctx, 2,
+				func(context.Context) error { return err1 },
+				func(ctx context.Context) error {
+					time.Sleep(10 * time.Millisecond)
+					return ctx.Err()
+				},
+			)
+			Expect(Errors(err)).To(ConsistOf(err1))
+		})
+	})
+
+	Describe("ParallelGraceful", func() {
+		It("should let the functions flush after cancellation before returning", func() {
+			var (
+				ctx, cancel = context.WithCancel(context.Background())
+				started     = make(chan struct{}, 2)
+				flushed     int32
+				fn          = func(ctx context.Context) error {
+					started <- struct{}{}
+					<-ctx.Done()
+					time.Sleep(20 * time.Millisecond)
+					atomic.AddInt32(&flushed, 1)
+					return nil
+				}
+			)
+			go func() {
+				<-started
+				<-started
+				cancel()
+			}()
+
+			Expect(ParallelGraceful(ctx, time.Second, fn, fn)).To(Succeed())
+			Expect(atomic.LoadInt32(&flushed)).To(Equal(int32(2)))
+		})
+
+		It("should abandon the functions that do not complete within the grace period", func() {
+			var (
+				ctx, cancel = context.WithCancel(context.Background())
+				release     = make(chan struct{})
+				err1        = mkError(1)
+				stuck       = func(context.Context) error {
+					<-release
+					return nil
+				}
+			)
+			defer close(release)
+			cancel()
+
+			err := ParallelGraceful(ctx, 20*time.Millisecond,
+				func(context.Context) error { return err1 },
+				stuck,
+				stuck,
+			)
+			Expect(ErrorsByIndex(err)).To(Equal(map[int]error{0: err1, 1: context.Canceled, 2: context.Canceled}))
+		})
+	})
+
+	Describe("ParallelTimeouts", func() {
+		It("should apply the timeout of every function to it only", func() {
+			var (
+				ctx  = context.TODO()
+				fast = func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				slow = func(ctx context.Context) error {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(50 * time.Millisecond):
+						return nil
+					}
+				}
+			)
+
+			err := ParallelTimeouts(ctx, []time.Duration{10 * time.Millisecond, 0}, fast, slow)
+			Expect(ErrorsByIndex(err)).To(Equal(map[int]error{0: context.DeadlineExceeded}))
+		})
+
+		It("should reject a mismatching number of timeouts without running the functions", func() {
+			f1 := mock.NewMockFunc(ctrl)
+
+			err := ParallelTimeouts(context.TODO(), []time.Duration{time.Second, time.Second}, f1.Call)
+			Expect(errors.Is(err, ErrLengthMismatch)).To(BeTrue())
+		})
+	})
+
+	Describe("ParallelTry", func() {
+		It("should count and collect only the functions that complete in time", func() {
+			var (
+				release = make(chan struct{})
+				err2    = mkError(2)
+			)
+			defer close(release)
+
+			completed, err := ParallelTry(context.TODO(), 50*time.Millisecond,
+				func(context.Context) error { return nil },
+				func(context.Context) error { return err2 },
+				func(context.Context) error { <-release; return nil },
+			)
+			Expect(completed).To(Equal(2))
+			Expect(Errors(err)).To(ConsistOf(err2))
+		})
+
+		It("should return once all functions completed", func() {
+			completed, err := ParallelTry(context.TODO(), time.Hour,
+				func(context.Context) error { return nil },
+				nil,
+				func(context.Context) error { return nil },
+			)
+			Expect(completed).To(Equal(2))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("ParallelTimed", func() {
+		It("should report the error and duration of every function", func() {
+			var (
+				err1  = mkError(1)
+				sleep = func(d time.Duration, err error) Func {
+					return func(context.Context) error {
+						time.Sleep(d)
+						return err
+					}
+				}
+			)
+
+			res := ParallelTimed(context.TODO(),
+				sleep(30*time.Millisecond, nil),
+				sleep(10*time.Millisecond, err1),
+				sleep(50*time.Millisecond, nil),
+			)
+			Expect(res).To(HaveLen(3))
+			for i, r := range res {
+				Expect(r.Index).To(Equal(i))
+			}
+			Expect(res[0].Err).NotTo(HaveOccurred())
+			Expect(res[1].Err).To(BeIdenticalTo(err1))
+			Expect(res[2].Err).NotTo(HaveOccurred())
+
+			Expect(res[1].Duration).To(BeNumerically(">=", 10*time.Millisecond))
+			Expect(res[1].Duration).To(BeNumerically("<", res[0].Duration))
+			Expect(res[0].Duration).To(BeNumerically("<", res[2].Duration))
+		})
+	})
+
+	Describe("ParallelWithCancel", func() {
+		It("should allow any function to cancel the group", func() {
+			var (
+				f1  = mock.NewMockFunc(ctrl)
+				f2  = mock.NewMockFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+
+			err := ParallelWithCancel(ctx,
+				func(ctx context.Context, cancel context.CancelFunc) error { return f1.Call(ctx) },
+				func(ctx context.Context, cancel context.CancelFunc) error { return f2.Call(ctx) },
+				func(ctx context.Context, cancel context.CancelFunc) error {
+					cancel()
+					return nil
+				},
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(cancellation, cancellation))
+		})
+	})
+
+	Describe("ParallelHandle", func() {
+		It("should cancel the running functions when calling cancel", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+				f3   = mock.NewMockFunc(ctrl)
+			)
+
+			f1.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+			f3.EXPECT().Call(gomock.Any()).Return(err1)
+
+			wait, cancel := ParallelHandle(context.TODO(), f1.Call, f2.Call, f3.Call)
+			cancel()
+
+			done := make(chan error, 1)
+			go func() { done <- wait() }()
+			var err error
+			Eventually(done).Should(Receive(&err))
+			Expect(Errors(err)).To(ConsistOf(err1, cancellation, cancellation))
+			Expect(wait()).To(Equal(err))
+		})
+
+		It("should return the errors once all functions completed without cancelling", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+			)
+
+			f1.EXPECT().Call(gomock.Any())
+			f2.EXPECT().Call(gomock.Any()).Return(err1)
+
+			wait, cancel := ParallelHandle(context.TODO(), f1.Call, f2.Call)
+			defer cancel()
+			Expect(Errors(wait())).To(ConsistOf(err1))
+		})
+	})
+
+	Describe("CoalesceString", func() {
+		It("should return the first non-empty result without running the rest", func() {
+			var (
+				f1  = mock.NewMockStringFunc(ctrl)
+				f2  = mock.NewMockStringFunc(ctrl)
+				f3  = mock.NewMockStringFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			gomock.InOrder(
+				f1.EXPECT().Call(ctx).Return("", nil),
+				f2.EXPECT().Call(ctx).Return("foo", nil),
+			)
+
+			Expect(CoalesceString(ctx, f1.Call, f2.Call, f3.Call)).To(Equal("foo"))
+		})
+
+		It("should return the empty string if all results are empty", func() {
+			var (
+				f1  = mock.NewMockStringFunc(ctrl)
+				f2  = mock.NewMockStringFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			gomock.InOrder(
+				f1.EXPECT().Call(ctx).Return("", nil),
+				f2.EXPECT().Call(ctx).Return("", nil),
+			)
+
+			Expect(CoalesceString(ctx, f1.Call, f2.Call)).To(BeEmpty())
+		})
+
+		It("should return the first error", func() {
+			var (
+				err2 = mkError(2)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+				f3   = mock.NewMockStringFunc(ctrl)
+				ctx  = context.TODO()
+			)
+
+			gomock.InOrder(
+				f1.EXPECT().Call(ctx).Return("", nil),
+				f2.EXPECT().Call(ctx).Return("", err2),
+			)
+
+			_, err := CoalesceString(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(BeIdenticalTo(err2))
+		})
+	})
+
+	Describe("Stages", func() {
+		It("should run the stages in order and stop at the first failing stage", func() {
+			var (
+				err3 = mkError(3)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+				f3   = mock.NewMockFunc(ctrl)
+				f4   = mock.NewMockFunc(ctrl)
+				f5   = mock.NewMockFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1Exec := f1.EXPECT().Call(ctx)
+			f2Exec := f2.EXPECT().Call(ctx)
+			f3.EXPECT().Call(ctx).After(f1Exec).After(f2Exec).Return(err3)
+			f4.EXPECT().Call(ctx).After(f1Exec).After(f2Exec)
+
+			err := Stages(ctx, []Func{f1.Call, f2.Call}, nil, []Func{f3.Call, f4.Call}, []Func{f5.Call})
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(err3))
+		})
+
+		It("should return nil if there are no stages", func() {
+			Expect(Stages(context.TODO())).To(Succeed())
+		})
+	})
+
+	Describe("Flatten", func() {
+		It("should concatenate the groups", func() {
+			var order []int
+			fn := func(i int) Func {
+				return func(context.Context) error {
+					order = append(order, i)
+					return nil
+				}
+			}
+
+			fns := Flatten([]Func{fn(0), fn(1)}, nil, []Func{fn(2)})
+			Expect(fns).To(HaveLen(3))
+			Expect(Sequence(context.TODO(), fns...)).To(Succeed())
+			Expect(order).To(Equal([]int{0, 1, 2}))
+		})
+	})
+
+	Describe("NamedGroups", func() {
+		It("should cancel the other groups with a cause naming the failed group", func() {
+			var (
+				err1   = mkError(1)
+				causes = make(chan error, 2)
+				wait   = func(ctx context.Context) error {
+					<-ctx.Done()
+					causes <- context.Cause(ctx)
+					return ctx.Err()
+				}
+			)
+
+			err := NamedGroups(context.TODO(), map[string][]Func{
+				"failing": {func(context.Context) error { return err1 }},
+				"waiting": {wait, wait},
+			})
+			Expect(err).To(MatchError(`group "failing" failed: error 1`))
+			Expect(errors.Is(err, err1)).To(BeTrue())
+			Expect(causes).To(Receive(BeIdenticalTo(err)))
+			Expect(causes).To(Receive(BeIdenticalTo(err)))
+		})
+
+		It("should return nil if all functions succeed", func() {
+			Expect(NamedGroups(context.TODO(), map[string][]Func{
+				"a": {func(context.Context) error { return nil }},
+				"b": {nil, func(context.Context) error { return nil }},
+			})).To(Succeed())
+		})
+	})
+
+	Describe("ParallelNamed", func() {
+		It("should return the errors of the failed functions by their names", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+				f3   = mock.NewMockFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(ctx)
+			f2.EXPECT().Call(ctx).Return(err1)
+			f3.EXPECT().Call(ctx)
+
+			errs := ParallelNamed(ctx, map[string]Func{"db": f1.Call, "cache": f2.Call, "queue": f3.Call})
+			Expect(errs).To(Equal(map[string]error{"cache": err1}))
+		})
+
+		It("should return an empty map if all functions succeeded", func() {
+			f1 := mock.NewMockFunc(ctrl)
+			f1.EXPECT().Call(gomock.Any())
+
+			errs := ParallelNamed(context.TODO(), map[string]Func{"a": f1.Call})
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("should report every function if the maximum fan-out is exceeded", func() {
+			errs := New(UnlimitedExecutor, WithMaxFanout(1)).ParallelNamed(context.TODO(), map[string]Func{
+				"a": mock.NewMockFunc(ctrl).Call,
+				"b": mock.NewMockFunc(ctrl).Call,
+			})
+			Expect(errs).To(HaveLen(2))
+			Expect(errors.Is(errs["a"], ErrFanoutExceeded)).To(BeTrue())
+			Expect(errors.Is(errs["b"], ErrFanoutExceeded)).To(BeTrue())
+		})
+	})
+
+	Describe("ParallelGroups", func() {
+		It("should attribute the errors to their groups", func() {
+			var (
+				err1 = mkError(1)
+				err2 = mkError(2)
+				err3 = mkError(3)
+				ok   = func(context.Context) error { return nil }
+				fail = func(err error) Func {
+					return func(context.Context) error { return err }
+				}
+			)
+
+			err := ParallelGroups(context.TODO(),
+				[]Func{ok, fail(err1)},
+				[]Func{ok},
+				[]Func{fail(err2), ok, fail(err3)},
+			)
+			var gerr *GroupError
+			Expect(errors.As(err, &gerr)).To(BeTrue())
+			Expect(gerr.Groups).To(HaveLen(2))
+			Expect(gerr.Groups[0]).To(ConsistOf(err1))
+			Expect(gerr.Groups[2]).To(ConsistOf(err2, err3))
+		})
+
+		It("should return no error if all functions succeed", func() {
+			ok := func(context.Context) error { return nil }
+			Expect(ParallelGroups(context.TODO(), []Func{ok}, []Func{ok, ok})).To(Succeed())
+		})
+	})
+
+	Describe("Race", func() {
+		It("should return the result of the first function and cancel the others", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockFunc(ctrl)
+				f2   = mock.NewMockFunc(ctrl)
+				f3   = mock.NewMockFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(err1)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnError)
+
+			err := Race(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeIdenticalTo(err1))
+		})
+	})
+
+	Describe("ParallelString", func() {
+		It("should run all computations, returning all errors and results", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+				f3   = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", err1)
+			f2.EXPECT().Call(gomock.Any()).Return("foo", nil)
+			f3.EXPECT().Call(gomock.Any()).Return("bar", nil)
+
+			res, err := ParallelString(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(err1))
+			Expect(res).To(ConsistOf("foo", "bar"))
+		})
+	})
+
+	Describe("ParallelStringCancelOnError", func() {
+		It("should run all computations, cancelling them when an error occurs", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+				f3   = mock.NewMockStringFunc(ctrl)
+
+				// f3 and f2 complete before f1 fails.
+				f = New(reverseExecutor(3))
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", err1)
+			f2.EXPECT().Call(gomock.Any()).Return("foo", nil)
+			f3.EXPECT().Call(gomock.Any()).Return("bar", nil)
+
+			res, err := f.ParallelStringCancelOnError(context.TODO(), f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(err1))
+			Expect(res).To(ConsistOf("foo", "bar"))
+		})
+
+		It("should return exactly the results received before the first error", func() {
+			var (
+				err3 = mkError(3)
+				f    = New(SyncExecutor)
+			)
+
+			res, err := f.ParallelStringCancelOnError(context.TODO(),
+				func(context.Context) (string, error) { return "a", nil },
+				func(context.Context) (string, error) { return "b", nil },
+				func(context.Context) (string, error) { return "", err3 },
+				func(context.Context) (string, error) { return "d", nil },
+			)
+			Expect(Errors(err)).To(Equal([]error{err3}))
+			Expect(res).To(Equal([]string{"a", "b"}))
+		})
+	})
+
+	Describe("ParallelStringIndexed", func() {
+		It("should return the results and errors by index", func() {
+			var (
+				err1 = mkError(1)
+				err3 = mkError(3)
+				f0   = mock.NewMockStringFunc(ctrl)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+				f3   = mock.NewMockStringFunc(ctrl)
+				f4   = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f0.EXPECT().Call(ctx).Return("a", nil)
+			f1.EXPECT().Call(ctx).Return("", err1)
+			f2.EXPECT().Call(ctx).Return("c", nil)
+			f3.EXPECT().Call(ctx).Return("", err3)
+			f4.EXPECT().Call(ctx).Return("e", nil)
+
+			res, errs := ParallelStringIndexed(ctx, f0.Call, f1.Call, f2.Call, f3.Call, f4.Call)
+			Expect(res).To(Equal([]string{"a", "", "c", "", "e"}))
+			Expect(errs).To(Equal(map[int]error{1: err1, 3: err3}))
+		})
+	})
+
+	Describe("ParallelNamedString", func() {
+		It("should return the results and errors by name", func() {
+			var (
+				err1 = mkError(1)
+				err2 = mkError(2)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+				f3   = mock.NewMockStringFunc(ctrl)
+				f4   = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(ctx).Return("from env", nil)
+			f2.EXPECT().Call(ctx).Return("", err1)
+			f3.EXPECT().Call(ctx).Return("from file", nil)
+			f4.EXPECT().Call(ctx).Return("", err2)
+
+			res, errs := ParallelNamedString(ctx, map[string]StringFunc{
+				"env":    f1.Call,
+				"vault":  f2.Call,
+				"file":   f3.Call,
+				"remote": f4.Call,
+			})
+			Expect(res).To(Equal(map[string]string{"env": "from env", "file": "from file"}))
+			Expect(errs).To(Equal(map[string]error{"vault": err1, "remote": err2}))
+		})
+
+		It("should return a nil error map if all functions succeeded", func() {
+			f1 := mock.NewMockStringFunc(ctrl)
+			f1.EXPECT().Call(gomock.Any()).Return("a", nil)
+
+			res, errs := ParallelNamedString(context.TODO(), map[string]StringFunc{"a": f1.Call})
+			Expect(res).To(Equal(map[string]string{"a": "a"}))
+			Expect(errs).To(BeNil())
+		})
+	})
+
+	Describe("ParallelStringWithDeadline", func() {
+		It("should return all results if all functions complete before the deadline", func() {
+			var (
+				f1 = mock.NewMockStringFunc(ctrl)
+				f2 = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("a", nil)
+			f2.EXPECT().Call(gomock.Any()).Return("b", nil)
+
+			res, err := ParallelStringWithDeadline(ctx, time.Second, f1.Call, f2.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(ConsistOf("a", "b"))
+		})
+
+		It("should return the partial results and cancel the slow functions at the deadline", func() {
+			var (
+				f1 = mock.NewMockStringFunc(ctrl)
+				f2 = mock.NewMockStringFunc(ctrl)
+				f3 = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("a", nil)
+			f2.EXPECT().Call(gomock.Any()).Return("b", nil)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+				return "", ctx.Err()
+			})
+
+			res, err := ParallelStringWithDeadline(ctx, 50*time.Millisecond, f1.Call, f2.Call, f3.Call)
+			Expect(Errors(err)).To(ConsistOf(ErrPartialDeadline))
+			Expect(res).To(ConsistOf("a", "b"))
+		})
+	})
+
+	Describe("FirstString", func() {
+		It("should return the first successful result even if a faster function fails", func() {
+			var (
+				f1 = mock.NewMockStringFunc(ctrl)
+				f2 = mock.NewMockStringFunc(ctrl)
+				f3 = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", mkError(1))
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(func(context.Context) (string, error) {
+				time.Sleep(10 * time.Millisecond)
+				return "slow", nil
+			})
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnStringError)
+
+			res, err := FirstString(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("slow"))
+		})
+
+		It("should return all errors if all functions fail", func() {
+			var (
+				err1 = mkError(1)
+				err2 = mkError(2)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", err1)
+			f2.EXPECT().Call(gomock.Any()).Return("", err2)
+
+			res, err := FirstString(ctx, f1.Call, f2.Call)
+			Expect(Errors(err)).To(ConsistOf(err1, err2))
+			Expect(res).To(BeEmpty())
+		})
+	})
+
+	Describe("RaceStringSuccess", func() {
+		It("should return the successful result of a slower function if the fastest fails", func() {
+			var (
+				f1 = mock.NewMockStringFunc(ctrl)
+				f2 = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", mkError(1))
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(func(context.Context) (string, error) {
+				time.Sleep(10 * time.Millisecond)
+				return "slow", nil
+			})
+
+			res, err := RaceStringSuccess(ctx, f1.Call, f2.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("slow"))
+		})
+
+		It("should return the last error if all functions fail", func() {
+			var (
+				err1 = mkError(1)
+				err2 = mkError(2)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", err1)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(func(context.Context) (string, error) {
+				time.Sleep(10 * time.Millisecond)
+				return "", err2
+			})
+
+			res, err := RaceStringSuccess(ctx, f1.Call, f2.Call)
+			Expect(err).To(BeIdenticalTo(err2))
+			Expect(res).To(BeEmpty())
+		})
+	})
+
+	Describe("RaceString", func() {
+		It("should run all computations, returning as soon as one of them finishes", func() {
+			var (
+				f1 = mock.NewMockStringFunc(ctrl)
+				f2 = mock.NewMockStringFunc(ctrl)
+				f3 = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("foo", nil)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnStringError)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnStringError)
+
+			res, err := RaceString(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("foo"))
+		})
+	})
+
+	Describe("QuorumString", func() {
+		It("should return the first k results and cancel the others", func() {
+			var (
+				f1 = mock.NewMockStringFunc(ctrl)
+				f2 = mock.NewMockStringFunc(ctrl)
+				f3 = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("foo", nil)
+			f2.EXPECT().Call(gomock.Any()).Return("bar", nil)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnStringError)
+
+			res, err := QuorumString(ctx, 2, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(ConsistOf("foo", "bar"))
+		})
+
+		It("should fail once the quorum became unreachable", func() {
+			var (
+				err1 = mkError(1)
+				err2 = mkError(2)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+				f3   = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", err1)
+			f2.EXPECT().Call(gomock.Any()).Return("", err2)
+			f3.EXPECT().Call(gomock.Any()).Return("foo", nil)
+
+			res, err := QuorumString(ctx, 2, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(err1, err2))
+			Expect(len(res)).To(BeNumerically("<=", 1))
+		})
+
+		It("should reject a quorum larger than the number of functions", func() {
+			f1 := mock.NewMockStringFunc(ctrl)
+
+			_, err := QuorumString(context.TODO(), 2, f1.Call)
+			Expect(err).To(BeIdenticalTo(ErrQuorumUnreachable))
+		})
+	})
+
+	Describe("ParallelInt", func() {
+		It("should run all computations, returning all errors and results", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockIntFunc(ctrl)
+				f2   = mock.NewMockIntFunc(ctrl)
+				f3   = mock.NewMockIntFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(0, err1)
+			f2.EXPECT().Call(gomock.Any()).Return(1, nil)
+			f3.EXPECT().Call(gomock.Any()).Return(2, nil)
+
+			res, err := ParallelInt(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(err1))
+			Expect(res).To(ConsistOf(1, 2))
+		})
+	})
+
+	Describe("ParallelIntInto", func() {
+		It("should send the successful results to the given channel", func() {
+			var (
+				err2 = mkError(2)
+				f1   = mock.NewMockIntFunc(ctrl)
+				f2   = mock.NewMockIntFunc(ctrl)
+				f3   = mock.NewMockIntFunc(ctrl)
+				out  = make(chan int, 3)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(1, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(0, err2)
+			f3.EXPECT().Call(gomock.Any()).Return(3, nil)
+
+			err := ParallelIntInto(ctx, out, f1.Call, f2.Call, f3.Call)
+			Expect(Errors(err)).To(ConsistOf(err2))
+			Expect(out).To(HaveLen(2))
+			Expect([]int{<-out, <-out}).To(ConsistOf(1, 3))
+			Expect(out).NotTo(BeClosed())
+		})
+	})
+
+	Describe("ParallelIntCancelOnError", func() {
+		It("should run all computations, cancelling them when an error occurs", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockIntFunc(ctrl)
+				f2   = mock.NewMockIntFunc(ctrl)
+				f3   = mock.NewMockIntFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(0, err1)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+
+			res, err := ParallelIntCancelOnError(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(err1, cancellation, cancellation))
+			Expect(res).To(BeEmpty())
+		})
+	})
+
+	Describe("ParallelIntCancelOnErrorIndexed", func() {
+		It("should return only the genuinely successful results by index", func() {
+			var (
+				err2 = mkError(2)
+				f1   = mock.NewMockIntFunc(ctrl)
+				f2   = mock.NewMockIntFunc(ctrl)
+				f3   = mock.NewMockIntFunc(ctrl)
+				f4   = mock.NewMockIntFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(1, nil)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+				time.Sleep(10 * time.Millisecond)
+				return 0, err2
+			})
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+			f4.EXPECT().Call(gomock.Any()).Return(4, nil)
+
+			res, err := ParallelIntCancelOnErrorIndexed(ctx, f1.Call, f2.Call, f3.Call, f4.Call)
+			Expect(Errors(err)).To(ConsistOf(err2))
+			Expect(ErrorsByIndex(err)).To(HaveKey(1))
+			Expect(res).To(Equal(map[int]int{0: 1, 3: 4}))
+		})
+
+		It("should return all results if all functions succeed", func() {
+			var (
+				f1 = mock.NewMockIntFunc(ctrl)
+				f2 = mock.NewMockIntFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(1, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(2, nil)
+
+			res, err := ParallelIntCancelOnErrorIndexed(ctx, f1.Call, f2.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(map[int]int{0: 1, 1: 2}))
+		})
+	})
+
+	Describe("RaceInt", func() {
+		It("should run all computations, returning as soon as one of them finishes", func() {
+			var (
+				f1 = mock.NewMockIntFunc(ctrl)
+				f2 = mock.NewMockIntFunc(ctrl)
+				f3 = mock.NewMockIntFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(1, nil)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+
+			res, err := RaceInt(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(1))
+		})
+	})
+
+	Describe("RaceIntIndexed", func() {
+		It("should return the index of the winning function", func() {
+			var (
+				f1 = mock.NewMockIntFunc(ctrl)
+				f2 = mock.NewMockIntFunc(ctrl)
+				f3 = mock.NewMockIntFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+			f2.EXPECT().Call(gomock.Any()).Return(42, nil)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+
+			value, index, err := RaceIntIndexed(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(42))
+			Expect(index).To(Equal(1))
+		})
+
+		It("should return -1 if no function was given", func() {
+			_, index, err := RaceIntIndexed(context.TODO())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(index).To(Equal(-1))
+		})
+	})
+
+	Describe("ParallelBool", func() {
+		It("should run all computations, returning all errors and results", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockBoolFunc(ctrl)
+				f2   = mock.NewMockBoolFunc(ctrl)
+				f3   = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(false, err1)
+			f2.EXPECT().Call(gomock.Any()).Return(false, nil)
+			f3.EXPECT().Call(gomock.Any()).Return(true, nil)
+
+			res, err := ParallelBool(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(err1))
+			Expect(res).To(ConsistOf(false, true))
+		})
+	})
+
+	Describe("ParallelBoolCancelOnError", func() {
+		It("should run all computations, cancelling them when an error occurs", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockBoolFunc(ctrl)
+				f2   = mock.NewMockBoolFunc(ctrl)
+				f3   = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(false, err1)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+
+			res, err := ParallelBoolCancelOnError(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).To(HaveOccurred())
+			Expect(Errors(err)).To(ConsistOf(err1, cancellation, cancellation))
+			Expect(res).To(BeEmpty())
+		})
+	})
+
+	Describe("RaceBool", func() {
+		It("should run all computations, returning as soon as one of them finishes", func() {
+			var (
+				f1 = mock.NewMockBoolFunc(ctrl)
+				f2 = mock.NewMockBoolFunc(ctrl)
+				f3 = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(true, nil)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+
+			res, err := RaceBool(ctx, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeTrue())
+		})
+	})
+
+	Describe("InFlight", func() {
+		It("should return to zero once the functions abandoned by a race completed", func() {
+			var (
+				f       = New(UnlimitedExecutor, WithDrainTimeout(time.Millisecond))
+				release = make(chan struct{})
+				err1    = mkError(1)
+			)
+
+			err := f.Race(context.TODO(),
+				func(context.Context) error { return err1 },
+				func(context.Context) error {
+					<-release
+					return nil
+				},
+			)
+			Expect(err).To(BeIdenticalTo(err1))
+			Expect(f.InFlight()).To(Equal(1))
+
+			close(release)
+			Eventually(f.InFlight).Should(Equal(0))
+		})
+
+		It("should be zero after an operation waiting for all functions", func() {
+			f := New(UnlimitedExecutor)
+			_, err := MapN(context.TODO(), f, 2, []int{1, 2, 3}, func(ctx context.Context, i int) (int, error) {
+				return i, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Race(context.TODO(), func(context.Context) error { return nil })).To(Succeed())
+			Expect(f.InFlight()).To(Equal(0))
+		})
+	})
+
+	Describe("RaceAny", func() {
+		It("should return the payload of the winner regardless of its type", func() {
+			ctx := context.TODO()
+
+			res, err := RaceAny(ctx,
+				func(context.Context) (interface{}, error) { return "a", nil },
+				func(ctx context.Context) (interface{}, error) {
+					<-ctx.Done()
+					return 1, ctx.Err()
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("a"))
+
+			res, err = RaceAny(ctx,
+				func(ctx context.Context) (interface{}, error) {
+					<-ctx.Done()
+					return "a", ctx.Err()
+				},
+				func(context.Context) (interface{}, error) { return 1, nil },
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(1))
+		})
+
+		It("should return the error of the winner", func() {
+			err1 := mkError(1)
+
+			res, err := RaceAny(context.TODO(),
+				func(context.Context) (interface{}, error) { return nil, err1 },
+				func(ctx context.Context) (interface{}, error) {
+					<-ctx.Done()
+					return 1, ctx.Err()
+				},
+			)
+			Expect(err).To(BeIdenticalTo(err1))
+			Expect(res).To(BeNil())
+		})
+	})
+
+	Describe("RaceCloser", func() {
+		It("should return the winner and close the losers exactly once", func() {
+			var (
+				winner = &countingCloser{}
+				loser1 = &countingCloser{}
+				loser2 = &countingCloser{}
+				ctx    = context.TODO()
+			)
+
+			closer, err := RaceCloser(ctx,
+				func(context.Context) (io.Closer, error) { return winner, nil },
+				func(ctx context.Context) (io.Closer, error) {
+					<-ctx.Done()
+					return loser1, nil
+				},
+				func(ctx context.Context) (io.Closer, error) {
+					<-ctx.Done()
+					return loser2, ctx.Err()
+				},
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(closer).To(BeIdenticalTo(winner))
+			Expect(winner.Closed()).To(BeZero())
+			Expect(loser1.Closed()).To(Equal(int32(1)))
+			Expect(loser2.Closed()).To(Equal(int32(1)))
+		})
+	})
+
+	Describe("RaceCond", func() {
+		It("should run all computations, returning as soon as one of them returns no error and true", func() {
+			var (
+				f1 = mock.NewMockBoolFunc(ctrl)
+				f2 = mock.NewMockBoolFunc(ctrl)
+				f3 = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(true, nil)
 			f2.EXPECT().Call(gomock.Any()).Return(false, nil)
 			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
 
@@ -378,5 +1950,510 @@ var _ = Describe("Flow", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(res).To(BeTrue())
 		})
+
+		It("should return the first received qualifying result if multiple functions qualify", func() {
+			var (
+				f1 = mock.NewMockBoolFunc(ctrl)
+				f2 = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(true, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(true, nil)
+
+			res, err := RaceCond(ctx, f1.Call, f2.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeTrue())
+		})
+
+		It("should return false if no function qualifies", func() {
+			var (
+				f1 = mock.NewMockBoolFunc(ctrl)
+				f2 = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(false, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(false, nil)
+
+			res, err := RaceCond(ctx, f1.Call, f2.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeFalse())
+		})
+	})
+
+	Describe("RaceFirst", func() {
+		It("should return the first result satisfying the predicate", func() {
+			var (
+				f1 = mock.NewMockIntFunc(ctrl)
+				f2 = mock.NewMockIntFunc(ctrl)
+				f3 = mock.NewMockIntFunc(ctrl)
+				f4 = mock.NewMockIntFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(3, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(10, nil)
+			f3.EXPECT().Call(gomock.Any()).Return(42, nil)
+			f4.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnIntError)
+
+			res, err := RaceFirst(ctx, Default, func(i int) bool { return i > 10 }, f1.Call, f2.Call, f3.Call, f4.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(42))
+		})
+
+		It("should return the zero value if no result satisfies the predicate", func() {
+			var (
+				f1 = mock.NewMockIntFunc(ctrl)
+				f2 = mock.NewMockIntFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(3, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(10, nil)
+
+			res, err := RaceFirst(ctx, Default, func(i int) bool { return i > 10 }, f1.Call, f2.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeZero())
+		})
+	})
+
+	Describe("ParallelFold", func() {
+		It("should fold the results as they arrive and collect the errors", func() {
+			var (
+				err3  = mkError(3)
+				delay = func(n int, err error) func(context.Context) (int, error) {
+					return func(context.Context) (int, error) {
+						time.Sleep(time.Duration(5-n) * time.Millisecond)
+						return n, err
+					}
+				}
+			)
+
+			sum, err := ParallelFold(context.TODO(), Default, 0, func(acc, n int) int { return acc + n },
+				delay(1, nil), delay(2, nil), delay(3, err3), delay(4, nil),
+			)
+			Expect(sum).To(Equal(7))
+			Expect(ErrorsByIndex(err)).To(Equal(map[int]error{2: err3}))
+		})
+
+		It("should return the initial value if no function is given", func() {
+			sum, err := ParallelFold[int](context.TODO(), Default, 42, func(acc, n int) int { return acc + n })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sum).To(Equal(42))
+		})
+	})
+
+	Describe("ParallelDetailed", func() {
+		It("should return the result, error and duration of every function in input order", func() {
+			var (
+				err2  = mkError(2)
+				delay = func(d time.Duration, s string, err error) func(context.Context) (string, error) {
+					return func(context.Context) (string, error) {
+						time.Sleep(d)
+						return s, err
+					}
+				}
+			)
+
+			res := ParallelDetailed(context.TODO(), Default,
+				delay(30*time.Millisecond, "slow", nil),
+				delay(0, "fast", nil),
+				delay(0, "", err2),
+				nil,
+			)
+			Expect(res).To(HaveLen(4))
+			for i, r := range res {
+				Expect(r.Index).To(Equal(i))
+			}
+			Expect(res[0].Value).To(Equal("slow"))
+			Expect(res[0].Err).NotTo(HaveOccurred())
+			Expect(res[0].Duration).To(BeNumerically(">=", 30*time.Millisecond))
+			Expect(res[1].Value).To(Equal("fast"))
+			Expect(res[1].Err).NotTo(HaveOccurred())
+			Expect(res[1].Duration).To(BeNumerically("<", res[0].Duration))
+			Expect(res[2].Err).To(BeIdenticalTo(err2))
+			Expect(res[3]).To(Equal(DetailedResult[string]{Index: 3}))
+		})
+
+		It("should report the fan-out error for every function", func() {
+			res := ParallelDetailed(context.TODO(), New(UnlimitedExecutor, WithMaxFanout(1)),
+				func(context.Context) (int, error) { return 1, nil },
+				func(context.Context) (int, error) { return 2, nil },
+			)
+			Expect(res).To(HaveLen(2))
+			for i, r := range res {
+				Expect(r.Index).To(Equal(i))
+				Expect(errors.Is(r.Err, ErrFanoutExceeded)).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("WithResultBuffer", func() {
+		It("should bound the results that were produced but not collected yet", func() {
+			const (
+				limit  = 2
+				buffer = 1
+			)
+			var (
+				ex                   = LimitExecutor(limit, UnlimitedExecutor)
+				started, uncollected int64
+				maxUncollected       int64
+				fns                  = make([]func(context.Context) (int, error), 20)
+			)
+			ex.Start()
+			defer ex.Stop()
+
+			for i := range fns {
+				fns[i] = func(context.Context) (int, error) {
+					n := atomic.AddInt64(&uncollected, 1)
+					atomic.AddInt64(&started, 1)
+					for {
+						max := atomic.LoadInt64(&maxUncollected)
+						if n <= max || atomic.CompareAndSwapInt64(&maxUncollected, max, n) {
+							break
+						}
+					}
+					return 1, nil
+				}
+			}
+
+			f := New(ex, WithResultBuffer(buffer))
+			sum, err := ParallelFold(context.TODO(), f, 0, func(acc, n int) int {
+				time.Sleep(2 * time.Millisecond)
+				atomic.AddInt64(&uncollected, -1)
+				return acc + n
+			}, fns...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sum).To(Equal(len(fns)))
+			Expect(atomic.LoadInt64(&started)).To(Equal(int64(len(fns))))
+			// The running or blocked functions, the buffered results and the result being collected.
+			Expect(atomic.LoadInt64(&maxUncollected)).To(BeNumerically("<=", limit+buffer+1))
+		})
+	})
+
+	Describe("RaceAtLeast", func() {
+		It("should return the first success among the first min completers", func() {
+			var (
+				err1 = mkError(1)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+				f3   = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", err1)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(func(context.Context) (string, error) {
+				time.Sleep(10 * time.Millisecond)
+				return "b", nil
+			})
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnStringError)
+
+			res, err := RaceAtLeast(ctx, Default, 2, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("b"))
+		})
+
+		It("should return the first error if none of the first min completers succeeded", func() {
+			var (
+				err1 = mkError(1)
+				err2 = mkError(2)
+				f1   = mock.NewMockStringFunc(ctrl)
+				f2   = mock.NewMockStringFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return("", err1)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(func(context.Context) (string, error) {
+				time.Sleep(10 * time.Millisecond)
+				return "", err2
+			})
+
+			_, err := RaceAtLeast(ctx, Default, 2, f1.Call, f2.Call)
+			Expect(err).To(Equal(err1))
+		})
+	})
+
+	Describe("WaitForAny", func() {
+		It("should return the index of the first check that becomes healthy", func() {
+			var (
+				polls1, polls2 int32
+				check1         = func(context.Context) (bool, error) {
+					atomic.AddInt32(&polls1, 1)
+					return false, mkError(1)
+				}
+				check2 = func(context.Context) (bool, error) {
+					return atomic.AddInt32(&polls2, 1) == 3, nil
+				}
+			)
+
+			i, err := WaitForAny(context.TODO(), 5*time.Millisecond, check1, check2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(i).To(Equal(1))
+			Expect(atomic.LoadInt32(&polls2)).To(Equal(int32(3)))
+
+			polls := atomic.LoadInt32(&polls1)
+			Consistently(func() int32 { return atomic.LoadInt32(&polls1) }, 50*time.Millisecond).Should(Equal(polls))
+		})
+
+		It("should return the context error if no check becomes healthy in time", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			i, err := WaitForAny(ctx, 5*time.Millisecond, func(context.Context) (bool, error) { return false, nil })
+			Expect(err).To(Equal(context.DeadlineExceeded))
+			Expect(i).To(Equal(-1))
+		})
+	})
+
+	Describe("VoteBool", func() {
+		It("should return true once the threshold is met and cancel the others", func() {
+			var (
+				f1 = mock.NewMockBoolFunc(ctrl)
+				f2 = mock.NewMockBoolFunc(ctrl)
+				f3 = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(true, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(true, nil)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+
+			res, err := VoteBool(ctx, 2, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeTrue())
+		})
+
+		It("should return false once the threshold became unreachable", func() {
+			var (
+				err2 = mkError(2)
+				f1   = mock.NewMockBoolFunc(ctrl)
+				f2   = mock.NewMockBoolFunc(ctrl)
+				f3   = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(false, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(false, err2)
+			f3.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+
+			res, err := VoteBool(ctx, 2, f1.Call, f2.Call, f3.Call)
+			Expect(Errors(err)).To(ConsistOf(err2))
+			Expect(res).To(BeFalse())
+		})
+
+		It("should reject a threshold larger than the number of functions", func() {
+			f1 := mock.NewMockBoolFunc(ctrl)
+
+			res, err := VoteBool(context.TODO(), 2, f1.Call, nil)
+			Expect(err).To(BeIdenticalTo(ErrQuorumUnreachable))
+			Expect(res).To(BeFalse())
+		})
+	})
+
+	Describe("SearchBool", func() {
+		It("should not launch further functions once one of them returns true", func() {
+			var (
+				f1 = mock.NewMockBoolFunc(ctrl)
+				f2 = mock.NewMockBoolFunc(ctrl)
+				f3 = mock.NewMockBoolFunc(ctrl)
+				f4 = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(true, nil)
+			f2.EXPECT().Call(gomock.Any()).DoAndReturn(waitForContextToErrorAndReturnBoolError)
+
+			res, err := SearchBool(ctx, 2, f1.Call, f2.Call, f3.Call, f4.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeTrue())
+		})
+
+		It("should run all functions if none of them qualifies", func() {
+			var (
+				f1 = mock.NewMockBoolFunc(ctrl)
+				f2 = mock.NewMockBoolFunc(ctrl)
+				f3 = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f1.EXPECT().Call(gomock.Any()).Return(false, nil)
+			f2.EXPECT().Call(gomock.Any()).Return(false, nil)
+			f3.EXPECT().Call(gomock.Any()).Return(false, nil)
+
+			res, err := SearchBool(ctx, 2, f1.Call, f2.Call, f3.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeFalse())
+		})
+
+		It("should return the context error if the search is aborted", func() {
+			var (
+				f1 = mock.NewMockBoolFunc(ctrl)
+				f2 = mock.NewMockBoolFunc(ctrl)
+
+				ctx, cancel = context.WithCancel(context.Background())
+			)
+
+			f1.EXPECT().Call(gomock.Any()).DoAndReturn(func(context.Context) (bool, error) {
+				cancel()
+				return false, nil
+			})
+
+			res, err := SearchBool(ctx, 1, f1.Call, f2.Call)
+			Expect(err).To(BeIdenticalTo(context.Canceled))
+			Expect(res).To(BeFalse())
+		})
+	})
+
+	Describe("single functions", func() {
+		It("should report the error of a single function like when submitting it", func() {
+			var (
+				err1 = mkError(1)
+				fn   = func(context.Context) error { return err1 }
+				ex   = LimitExecutor(1, UnlimitedExecutor)
+			)
+			ex.Start()
+			defer ex.Stop()
+
+			for _, f := range []*Flow{New(UnlimitedExecutor), New(ex)} {
+				err := f.Parallel(context.TODO(), fn)
+				Expect(Errors(err)).To(ConsistOf(err1))
+				Expect(ErrorsByIndex(err)).To(Equal(map[int]error{0: err1}))
+
+				Expect(f.Race(context.TODO(), fn)).To(BeIdenticalTo(err1))
+			}
+		})
+
+		It("should apply the options of the flow to a single function", func() {
+			f := New(UnlimitedExecutor, WithBatchInfo(), WithErrorAggregator(func(errs []error) error {
+				return fmt.Errorf("%d failed", len(errs))
+			}))
+
+			err := f.Parallel(context.TODO(), func(ctx context.Context) error {
+				index, total, ok := BatchInfo(ctx)
+				Expect(ok).To(BeTrue())
+				Expect(index).To(Equal(0))
+				Expect(total).To(Equal(1))
+				Expect(f.InFlight()).To(Equal(1))
+				return mkError(1)
+			})
+			Expect(err).To(MatchError("1 failed"))
+			Expect(f.InFlight()).To(Equal(0))
+		})
+
+		It("should provide the batch info to a single function of Race like when submitting it", func() {
+			ex := LimitExecutor(1, UnlimitedExecutor)
+			ex.Start()
+			defer ex.Stop()
+
+			for _, f := range []*Flow{New(UnlimitedExecutor, WithBatchInfo()), New(ex, WithBatchInfo())} {
+				Expect(f.Race(context.TODO(), func(ctx context.Context) error {
+					index, total, ok := BatchInfo(ctx)
+					if !ok || index != 0 || total != 1 {
+						return fmt.Errorf("batch info %d/%d, %t", index, total, ok)
+					}
+					return nil
+				})).To(Succeed())
+			}
+		})
+
+		It("should succeed for a single nil function", func() {
+			Expect(Parallel(context.TODO(), nil)).To(Succeed())
+			Expect(Race(context.TODO(), nil)).To(Succeed())
+		})
+	})
+
+	Describe("nil functions", func() {
+		It("should skip nil functions in the parallel families", func() {
+			var (
+				f    = mock.NewMockFunc(ctrl)
+				sf   = mock.NewMockStringFunc(ctrl)
+				intF = mock.NewMockIntFunc(ctrl)
+				bf   = mock.NewMockBoolFunc(ctrl)
+
+				ctx = context.TODO()
+			)
+
+			f.EXPECT().Call(gomock.Any()).Times(2)
+			sf.EXPECT().Call(gomock.Any()).Return("foo", nil)
+			intF.EXPECT().Call(gomock.Any()).Return(1, nil)
+			bf.EXPECT().Call(gomock.Any()).Return(true, nil)
+
+			Expect(Parallel(ctx, nil, f.Call, nil)).To(Succeed())
+			Expect(Race(ctx, nil, f.Call)).To(Succeed())
+
+			strs, err := ParallelString(ctx, nil, sf.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strs).To(ConsistOf("foo"))
+
+			i, err := RaceInt(ctx, nil, intF.Call)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(i).To(Equal(1))
+
+			b, err := RaceCond(ctx, bf.Call, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b).To(BeTrue())
+		})
+
+		It("should reject nil functions in Sequence before running any function", func() {
+			f1 := mock.NewMockFunc(ctrl)
+
+			Expect(Sequence(context.TODO(), f1.Call, nil)).To(BeIdenticalTo(ErrNilFunc))
+		})
 	})
 })
+
+func benchmarkParallel(b *testing.B, n int) {
+	var (
+		fns = make([]Func, n)
+		ctx = context.Background()
+	)
+	for i := range fns {
+		fns[i] = func(context.Context) error { return nil }
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Parallel(ctx, fns...)
+	}
+}
+
+func BenchmarkParallel1(b *testing.B) {
+	benchmarkParallel(b, 1)
+}
+
+func BenchmarkParallel10(b *testing.B) {
+	benchmarkParallel(b, 10)
+}
+
+func BenchmarkParallel1k(b *testing.B) {
+	benchmarkParallel(b, 1000)
+}
+
+func BenchmarkParallel100k(b *testing.B) {
+	benchmarkParallel(b, 100000)
+}
+
+func BenchmarkRace1(b *testing.B) {
+	var (
+		fns = []Func{func(context.Context) error { return nil }}
+		ctx = context.Background()
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Race(ctx, fns...)
+	}
+}
@@ -49,3 +49,19 @@ func ExampleParallel() {
 	// f2 running
 	// error
 }
+
+// A Flow using the SyncExecutor runs its functions in input order, making Race deterministic.
+func ExampleSyncExecutor() {
+	var (
+		f  = New(SyncExecutor)
+		f1 = func(ctx context.Context) (string, error) { return "first", nil }
+		f2 = func(ctx context.Context) (string, error) { return "second", nil }
+	)
+
+	res, err := f.RaceString(context.Background(), f1, f2)
+	if err != nil {
+		log.Fatalf("Error occurred: %v", err)
+	}
+	fmt.Println(res, f.Executor() == SyncExecutor)
+	// Output: first true
+}
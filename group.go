@@ -0,0 +1,50 @@
+package flow
+
+import (
+	"context"
+	"sync"
+)
+
+// FlowGroup runs functions like an errgroup.Group, submitting them to the executor of a Flow.
+type FlowGroup struct {
+	executor Executor
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	once sync.Once
+	err  error
+}
+
+// Group creates a new FlowGroup running its functions on the executor of f and an associated context
+// derived from ctx, mirroring errgroup.WithContext.
+//
+// The derived context is cancelled the first time a function of the group fails or once Wait returns,
+// whichever occurs first.
+func Group(ctx context.Context, f *Flow) (*FlowGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &FlowGroup{executor: f.executorFor(ctx), cancel: cancel}, ctx
+}
+
+// Go runs fn on the executor of the Flow.
+//
+// The first call to return a non-nil error cancels the context of the group; its error will be
+// returned by Wait.
+func (g *FlowGroup) Go(fn func() error) {
+	g.wg.Add(1)
+	g.executor.Submit(func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	})
+}
+
+// Wait blocks until all functions of the group have completed, then returns the first non-nil error, if any.
+func (g *FlowGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,69 @@
+package flow_test
+
+import (
+	"context"
+	"sync/atomic"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Group", func() {
+	It("should return no error and cancel the context if all functions succeed", func() {
+		var (
+			g, ctx = Group(context.TODO(), Default)
+			calls  int32
+		)
+
+		for i := 0; i < 3; i++ {
+			g.Go(func() error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			})
+		}
+
+		Expect(g.Wait()).To(Succeed())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(3)))
+		Expect(ctx.Err()).To(Equal(context.Canceled))
+	})
+
+	It("should cancel the context on the first error and return it", func() {
+		var (
+			g, ctx = Group(context.TODO(), Default)
+			err1   = mkError(1)
+		)
+
+		g.Go(func() error { return err1 })
+		g.Go(func() error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		Expect(g.Wait()).To(Equal(err1))
+	})
+
+	It("should bound the concurrency by the executor of the Flow", func() {
+		var (
+			ex      = LimitExecutor(1, UnlimitedExecutor)
+			g, _    = Group(context.TODO(), New(ex))
+			running int32
+			max     int32
+		)
+		ex.Start()
+		defer ex.Stop()
+
+		for i := 0; i < 5; i++ {
+			g.Go(func() error {
+				if n := atomic.AddInt32(&running, 1); n > atomic.LoadInt32(&max) {
+					atomic.StoreInt32(&max, n)
+				}
+				defer atomic.AddInt32(&running, -1)
+				return nil
+			})
+		}
+
+		Expect(g.Wait()).To(Succeed())
+		Expect(atomic.LoadInt32(&max)).To(Equal(int32(1)))
+	})
+})
@@ -0,0 +1,47 @@
+package flow
+
+import "encoding/json"
+
+// errorsJSON is the JSON representation of the errors of a parallel execution.
+type errorsJSON struct {
+	Errors []string `json:"errors"`
+	// Indices holds the index of the failed function for every error, or -1 if it is unknown.
+	// It is omitted if no index is known.
+	Indices []int `json:"indices,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m multiError) MarshalJSON() ([]byte, error) {
+	var (
+		out     = errorsJSON{Errors: make([]string, len(m))}
+		indices = make([]int, len(m))
+		indexed bool
+	)
+	for i, err := range m {
+		out.Errors[i] = err.Error()
+		indices[i] = -1
+		if ierr, ok := err.(indexedError); ok {
+			out.Errors[i] = ierr.err.Error()
+			indices[i] = ierr.index
+			indexed = true
+		}
+	}
+	if indexed {
+		out.Indices = indices
+	}
+	return json.Marshal(out)
+}
+
+// ErrorsJSON encodes err as a JSON object holding the messages of its causes, e.g. {"errors":["msg1","msg2"]}.
+//
+// If err was returned by a parallel execution, the object also holds the indices of the failed
+// functions, e.g. {"errors":["msg1","msg2"],"indices":[0,2]}, with -1 for errors that are not
+// associated with a function. Other errors are encoded as the only cause; nil is encoded without
+// causes.
+func ErrorsJSON(err error) ([]byte, error) {
+	m, ok := err.(multiError)
+	if !ok && err != nil {
+		m = multiError{err}
+	}
+	return m.MarshalJSON()
+}
@@ -0,0 +1,47 @@
+package flow_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON", func() {
+	Describe("ErrorsJSON", func() {
+		It("should encode the errors of a parallel execution with their indices", func() {
+			err := New(SyncExecutor).Parallel(context.TODO(),
+				func(context.Context) error { return mkError(0) },
+				func(context.Context) error { return nil },
+				func(context.Context) error { return mkError(2) },
+			)
+
+			data, jerr := ErrorsJSON(err)
+			Expect(jerr).NotTo(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"errors":["error 0","error 2"],"indices":[0,2]}`))
+		})
+
+		It("should encode a single wrapped error as the only cause", func() {
+			data, err := ErrorsJSON(fmt.Errorf("wrapped: %w", mkError(1)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"errors":["wrapped: error 1"]}`))
+		})
+
+		It("should encode nil without causes", func() {
+			data, err := ErrorsJSON(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(MatchJSON(`{"errors":[]}`))
+		})
+	})
+
+	It("should make the errors of a parallel execution marshalable with encoding/json", func() {
+		err := Parallel(context.TODO(), func(context.Context) error { return mkError(0) })
+
+		data, jerr := json.Marshal(err)
+		Expect(jerr).NotTo(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"errors":["error 0"],"indices":[0]}`))
+	})
+})
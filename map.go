@@ -0,0 +1,259 @@
+package flow
+
+import (
+	"context"
+	"sync"
+)
+
+// MapN runs fn for every element of in, with at most n invocations running at the same time.
+//
+// The results are returned in the order of in; the slots of failed elements hold the zero value.
+// It collects all the errors in the returned error. To obtain the multiple errors, use the
+// `Errors` function. If the context expires before all elements were launched, the remaining
+// elements are skipped and the context error is collected.
+// If n is not positive, all elements may be processed at the same time. If the Flow rejects the
+// execution, e.g. because its maximum fan-out is exceeded, fn is not run and only that error is returned.
+func MapN[In, Out any](ctx context.Context, f *Flow, n int, in []In, fn func(context.Context, In) (Out, error)) ([]Out, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(in)); err != nil {
+		return nil, err
+	}
+	if n <= 0 || n > len(in) {
+		n = len(in)
+	}
+
+	var (
+		executor = f.executorFor(ctx)
+		sem      = newSemaphore(n)
+		out      = make([]Out, len(in))
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		errs     multiError
+	)
+	for i, item := range in {
+		if err := sem.acquire(ctx); err != nil {
+			lock.Lock()
+			errs = append(errs, err)
+			lock.Unlock()
+			break
+		}
+
+		i, item, done := i, item, f.track()
+		wg.Add(1)
+		executor.Submit(func() {
+			defer wg.Done()
+			defer done()
+			defer sem.release()
+
+			res, err := fn(f.withBatchInfo(ctx, i, len(in)), item)
+			if err != nil {
+				lock.Lock()
+				defer lock.Unlock()
+				errs = append(errs, indexedError{i, err})
+				return
+			}
+			out[i] = res
+		})
+	}
+	wg.Wait()
+	return out, f.aggregate(errs)
+}
+
+// Filter runs pred for every element of in in parallel, returning the elements for which it returned true.
+//
+// The returned elements keep the order of in. Elements whose predicate failed are excluded.
+// It collects all the errors in the returned error. To obtain the multiple errors, use the
+// `Errors` function.
+func Filter[T any](ctx context.Context, f *Flow, in []T, pred func(context.Context, T) (bool, error)) ([]T, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(in)); err != nil {
+		return nil, err
+	}
+
+	var (
+		keep    = make([]bool, len(in))
+		results = make(chan error, len(in))
+	)
+	f.runAll(ctx, len(in), func(ctx context.Context, i int) {
+		ok, err := pred(ctx, in[i])
+		keep[i] = ok && err == nil
+		results <- withIndex(i, err)
+	}, func() { close(results) })
+
+	var errs multiError
+	for err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var out []T
+	for i, item := range in {
+		if keep[i] {
+			out = append(out, item)
+		}
+	}
+	return out, f.aggregate(errs)
+}
+
+// MapIndexed runs fn for every element of in in parallel, returning the results and errors by index.
+//
+// The i-th result holds the result for the i-th element, or the zero value if it failed. The errors
+// of the failed elements are returned by their index; if no element failed, the returned map is nil.
+// If the Flow rejects the execution, e.g. because its maximum fan-out is exceeded, fn is not run
+// and that error is returned for every index.
+func MapIndexed[In, Out any](ctx context.Context, f *Flow, in []In, fn func(context.Context, In) (Out, error)) ([]Out, map[int]error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+
+	out := make([]Out, len(in))
+	if err := f.check(ctx, len(in)); err != nil {
+		errs := make(map[int]error, len(in))
+		for i := range in {
+			errs[i] = err
+		}
+		return out, errs
+	}
+
+	results := make(chan error, len(in))
+	f.runAll(ctx, len(in), func(ctx context.Context, i int) {
+		res, err := fn(ctx, in[i])
+		if err != nil {
+			results <- withIndex(i, err)
+			return
+		}
+		out[i] = res
+	}, func() { close(results) })
+
+	var errs map[int]error
+	for err := range results {
+		if errs == nil {
+			errs = make(map[int]error)
+		}
+		ierr := err.(indexedError)
+		errs[ierr.index] = ierr.err
+	}
+	return out, errs
+}
+
+// ForEach runs fn for every element of in in parallel.
+//
+// It collects all the errors in the returned error. To obtain the multiple errors, use the
+// `Errors` function.
+func ForEach[T any](ctx context.Context, f *Flow, in []T, fn func(context.Context, T) error) error {
+	return ForEachIndexed(ctx, f, in, func(ctx context.Context, _ int, item T) error {
+		return fn(ctx, item)
+	})
+}
+
+// ForEachIndexed runs fn for every element of in in parallel, passing it the index of the element.
+//
+// It collects all the errors in the returned error. To obtain the multiple errors, use the
+// `Errors` function.
+func ForEachIndexed[T any](ctx context.Context, f *Flow, in []T, fn func(context.Context, int, T) error) error {
+	if len(in) == 0 {
+		return nil
+	}
+	if err := f.check(ctx, len(in)); err != nil {
+		return err
+	}
+
+	results := make(chan error, len(in))
+	f.runAll(ctx, len(in), func(ctx context.Context, i int) {
+		results <- withIndex(i, fn(ctx, i, in[i]))
+	}, func() { close(results) })
+
+	var errs multiError
+	for err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return f.aggregate(errs)
+}
+
+// Pair holds an input element together with the output it produced.
+type Pair[In, Out any] struct {
+	In  In
+	Out Out
+}
+
+// MapPairs runs fn for every element of in in parallel, returning the successful results paired with their input.
+//
+// The returned pairs keep the order of in. Elements whose function failed are excluded.
+// It collects all the errors in the returned error. To obtain the multiple errors, use the
+// `Errors` function.
+func MapPairs[In, Out any](ctx context.Context, f *Flow, in []In, fn func(context.Context, In) (Out, error)) ([]Pair[In, Out], error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	if err := f.check(ctx, len(in)); err != nil {
+		return nil, err
+	}
+
+	var (
+		out     = make([]Out, len(in))
+		ok      = make([]bool, len(in))
+		results = make(chan error, len(in))
+	)
+	f.runAll(ctx, len(in), func(ctx context.Context, i int) {
+		res, err := fn(ctx, in[i])
+		out[i], ok[i] = res, err == nil
+		results <- withIndex(i, err)
+	}, func() { close(results) })
+
+	var errs multiError
+	for err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var pairs []Pair[In, Out]
+	for i, item := range in {
+		if ok[i] {
+			pairs = append(pairs, Pair[In, Out]{In: item, Out: out[i]})
+		}
+	}
+	return pairs, f.aggregate(errs)
+}
+
+// ChunkProcess splits in into the given number of chunks and runs fn for every chunk in parallel.
+//
+// The chunks are contiguous and their sizes differ by at most one. If chunks exceeds the number of
+// elements, every element forms its own chunk; if chunks is not positive, in is processed as a single
+// chunk. It collects all the errors in the returned error. To obtain the multiple errors, use the
+// `Errors` function; the errors are indexed by chunk.
+func ChunkProcess[T any](ctx context.Context, f *Flow, in []T, chunks int, fn func(context.Context, []T) error) error {
+	if len(in) == 0 {
+		return nil
+	}
+	if chunks <= 0 {
+		chunks = 1
+	}
+	if chunks > len(in) {
+		chunks = len(in)
+	}
+
+	var (
+		parts = make([][]T, chunks)
+		size  = len(in) / chunks
+		extra = len(in) % chunks
+		lo    int
+	)
+	for i := range parts {
+		hi := lo + size
+		if i < extra {
+			hi++
+		}
+		// Cap the chunks so that appending to one of them does not overwrite the next one.
+		parts[i] = in[lo:hi:hi]
+		lo = hi
+	}
+	return ForEach(ctx, f, parts, fn)
+}
@@ -0,0 +1,231 @@
+package flow_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Map", func() {
+	Describe("MapN", func() {
+		It("should preserve the order and never exceed the concurrency limit", func() {
+			var (
+				in                   = make([]int, 100)
+				expected             = make([]int, 100)
+				running, maxObserved int64
+			)
+			for i := range in {
+				in[i] = i
+				expected[i] = 2 * i
+			}
+
+			out, err := MapN(context.TODO(), Default, 4, in, func(ctx context.Context, i int) (int, error) {
+				current := atomic.AddInt64(&running, 1)
+				defer atomic.AddInt64(&running, -1)
+				for {
+					max := atomic.LoadInt64(&maxObserved)
+					if current <= max || atomic.CompareAndSwapInt64(&maxObserved, max, current) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return 2 * i, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(Equal(expected))
+			Expect(atomic.LoadInt64(&maxObserved)).To(BeNumerically("<=", 4))
+		})
+
+		It("should collect the errors and leave the failed slots empty", func() {
+			err1 := mkError(1)
+
+			out, err := MapN(context.TODO(), Default, 2, []int{1, 2, 3}, func(ctx context.Context, i int) (int, error) {
+				if i == 2 {
+					return 0, err1
+				}
+				return i, nil
+			})
+			Expect(Errors(err)).To(ConsistOf(err1))
+			Expect(out).To(Equal([]int{1, 0, 3}))
+		})
+	})
+
+	Describe("Filter", func() {
+		It("should return the matching elements in order and collect the errors", func() {
+			err5 := mkError(5)
+
+			out, err := Filter(context.TODO(), Default, []int{1, 2, 3, 4, 5, 6}, func(ctx context.Context, i int) (bool, error) {
+				if i == 5 {
+					return false, err5
+				}
+				return i%2 == 0, nil
+			})
+			Expect(Errors(err)).To(ConsistOf(err5))
+			Expect(ErrorsByIndex(err)).To(Equal(map[int]error{4: err5}))
+			Expect(out).To(Equal([]int{2, 4, 6}))
+		})
+	})
+
+	Describe("MapIndexed", func() {
+		It("should return the results and the errors by index", func() {
+			var (
+				in   = []int{0, 1, 2, 3, 4, 5}
+				err1 = mkError(1)
+				err4 = mkError(4)
+			)
+
+			out, errs := MapIndexed(context.TODO(), Default, in, func(ctx context.Context, i int) (int, error) {
+				switch i {
+				case 1:
+					return 0, err1
+				case 4:
+					return 0, err4
+				}
+				return 2 * i, nil
+			})
+			Expect(out).To(Equal([]int{0, 0, 4, 6, 0, 10}))
+			Expect(errs).To(Equal(map[int]error{1: err1, 4: err4}))
+		})
+
+		It("should return a nil map if no element failed", func() {
+			out, errs := MapIndexed(context.TODO(), Default, []int{1, 2}, func(ctx context.Context, i int) (int, error) {
+				return i, nil
+			})
+			Expect(out).To(Equal([]int{1, 2}))
+			Expect(errs).To(BeNil())
+		})
+	})
+
+	Describe("ForEach", func() {
+		It("should process every element and collect the errors", func() {
+			var (
+				in   = []int64{1, 2, 3, 4, 5}
+				sum  int64
+				err3 = mkError(3)
+			)
+
+			err := ForEach(context.TODO(), Default, in, func(ctx context.Context, i int64) error {
+				atomic.AddInt64(&sum, i)
+				if i == 3 {
+					return err3
+				}
+				return nil
+			})
+			Expect(Errors(err)).To(ConsistOf(err3))
+			Expect(atomic.LoadInt64(&sum)).To(Equal(int64(15)))
+		})
+
+		It("should return nil for an empty slice", func() {
+			Expect(ForEach(context.TODO(), Default, nil, func(ctx context.Context, i int) error {
+				return mkError(i)
+			})).To(Succeed())
+		})
+	})
+
+	Describe("ForEachIndexed", func() {
+		It("should pass the index of every element and collect the errors by index", func() {
+			var (
+				in   = []string{"a", "b", "c"}
+				seen = make([]string, len(in))
+				err1 = mkError(1)
+			)
+
+			err := ForEachIndexed(context.TODO(), Default, in, func(ctx context.Context, i int, s string) error {
+				seen[i] = s
+				if i == 1 {
+					return err1
+				}
+				return nil
+			})
+			Expect(ErrorsByIndex(err)).To(Equal(map[int]error{1: err1}))
+			Expect(seen).To(Equal(in))
+		})
+	})
+
+	Describe("MapPairs", func() {
+		It("should return the successful results paired with their input and collect the errors", func() {
+			var (
+				err2 = mkError(2)
+				err5 = mkError(5)
+			)
+
+			pairs, err := MapPairs(context.TODO(), Default, []int{1, 2, 3, 4, 5}, func(ctx context.Context, i int) (string, error) {
+				switch i {
+				case 2:
+					return "", err2
+				case 5:
+					return "", err5
+				}
+				return strconv.Itoa(i * 10), nil
+			})
+			Expect(Errors(err)).To(ConsistOf(err2, err5))
+			Expect(pairs).To(Equal([]Pair[int, string]{
+				{In: 1, Out: "10"},
+				{In: 3, Out: "30"},
+				{In: 4, Out: "40"},
+			}))
+		})
+	})
+
+	Describe("ChunkProcess", func() {
+		var (
+			lock   sync.Mutex
+			chunks [][]int
+			record = func(ctx context.Context, chunk []int) error {
+				lock.Lock()
+				defer lock.Unlock()
+				chunks = append(chunks, chunk)
+				return nil
+			}
+			sizes = func() []int {
+				out := make([]int, len(chunks))
+				for i, chunk := range chunks {
+					out[i] = len(chunk)
+				}
+				return out
+			}
+			in = []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		)
+		BeforeEach(func() {
+			chunks = nil
+		})
+
+		It("should split the elements into balanced chunks, every element landing in exactly one", func() {
+			Expect(ChunkProcess(context.TODO(), Default, in, 3, record)).To(Succeed())
+			Expect(sizes()).To(ConsistOf(4, 3, 3))
+
+			var all []int
+			for _, chunk := range chunks {
+				all = append(all, chunk...)
+			}
+			Expect(all).To(ConsistOf(in))
+		})
+
+		It("should process every element on its own if there are more chunks than elements", func() {
+			Expect(ChunkProcess(context.TODO(), Default, in[:3], 5, record)).To(Succeed())
+			Expect(sizes()).To(Equal([]int{1, 1, 1}))
+		})
+
+		It("should process all elements as a single chunk if the number of chunks is not positive", func() {
+			Expect(ChunkProcess(context.TODO(), Default, in, 0, record)).To(Succeed())
+			Expect(chunks).To(Equal([][]int{in}))
+		})
+
+		It("should collect the errors by chunk", func() {
+			err1 := mkError(1)
+			err := ChunkProcess(context.TODO(), Default, in, 2, func(ctx context.Context, chunk []int) error {
+				if chunk[0] != 0 {
+					return err1
+				}
+				return nil
+			})
+			Expect(ErrorsByIndex(err)).To(Equal(map[int]error{1: err1}))
+		})
+	})
+})
@@ -0,0 +1,146 @@
+package flow
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+type stringCall struct {
+	done chan struct{}
+	item string
+	err  error
+}
+
+type stringMemo struct {
+	fn  StringFunc
+	ttl time.Duration
+
+	lock    sync.Mutex
+	item    string
+	expires time.Time
+	cached  bool
+	call    *stringCall
+}
+
+// MemoizeString creates a StringFunc that caches the result of the first successful call of fn for ttl.
+//
+// Calls while the cache is cold are coalesced into a single invocation of fn, using the context of
+// the call that triggered the invocation. Errors are not cached. If fn panics, the panic propagates
+// to the triggering call and the coalesced calls fail with a *PanicError.
+func MemoizeString(fn StringFunc, ttl time.Duration) StringFunc {
+	m := &stringMemo{fn: fn, ttl: ttl}
+	return m.get
+}
+
+func (m *stringMemo) get(ctx context.Context) (string, error) {
+	m.lock.Lock()
+	if m.cached && time.Now().Before(m.expires) {
+		item := m.item
+		m.lock.Unlock()
+		return item, nil
+	}
+
+	if c := m.call; c != nil {
+		m.lock.Unlock()
+		select {
+		case <-c.done:
+			return c.item, c.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	c := &stringCall{done: make(chan struct{})}
+	m.call = c
+	m.lock.Unlock()
+
+	defer func() {
+		// If fn panics, the waiting calls fail with the panic, which is re-raised for this call, and
+		// the next call invokes fn again.
+		if r := recover(); r != nil {
+			c.err = &PanicError{Value: r, Stack: debug.Stack()}
+			defer panic(r)
+		}
+
+		m.lock.Lock()
+		m.call = nil
+		if c.err == nil {
+			m.item, m.expires, m.cached = c.item, time.Now().Add(m.ttl), true
+		}
+		m.lock.Unlock()
+		close(c.done)
+	}()
+	c.item, c.err = m.fn(ctx)
+	return c.item, c.err
+}
+
+type intCall struct {
+	done chan struct{}
+	item int
+	err  error
+}
+
+type intMemo struct {
+	fn  IntFunc
+	ttl time.Duration
+
+	lock    sync.Mutex
+	item    int
+	expires time.Time
+	cached  bool
+	call    *intCall
+}
+
+// MemoizeInt creates an IntFunc that caches the result of the first successful call of fn for ttl.
+//
+// Calls while the cache is cold are coalesced into a single invocation of fn, using the context of
+// the call that triggered the invocation. Errors are not cached. If fn panics, the panic propagates
+// to the triggering call and the coalesced calls fail with a *PanicError.
+func MemoizeInt(fn IntFunc, ttl time.Duration) IntFunc {
+	m := &intMemo{fn: fn, ttl: ttl}
+	return m.get
+}
+
+func (m *intMemo) get(ctx context.Context) (int, error) {
+	m.lock.Lock()
+	if m.cached && time.Now().Before(m.expires) {
+		item := m.item
+		m.lock.Unlock()
+		return item, nil
+	}
+
+	if c := m.call; c != nil {
+		m.lock.Unlock()
+		select {
+		case <-c.done:
+			return c.item, c.err
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	c := &intCall{done: make(chan struct{})}
+	m.call = c
+	m.lock.Unlock()
+
+	defer func() {
+		// If fn panics, the waiting calls fail with the panic, which is re-raised for this call, and
+		// the next call invokes fn again.
+		if r := recover(); r != nil {
+			c.err = &PanicError{Value: r, Stack: debug.Stack()}
+			defer panic(r)
+		}
+
+		m.lock.Lock()
+		m.call = nil
+		if c.err == nil {
+			m.item, m.expires, m.cached = c.item, time.Now().Add(m.ttl), true
+		}
+		m.lock.Unlock()
+		close(c.done)
+	}()
+	c.item, c.err = m.fn(ctx)
+	return c.item, c.err
+}
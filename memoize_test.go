@@ -0,0 +1,164 @@
+package flow_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/adracus/flow"
+	"github.com/adracus/flow/mock"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Memoize", func() {
+	var ctrl *gomock.Controller
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("MemoizeString", func() {
+		It("should call the function once within the ttl and again after it expired", func() {
+			var (
+				f   = mock.NewMockStringFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			f.EXPECT().Call(ctx).Return("foo", nil).Times(2)
+
+			memo := MemoizeString(f.Call, 50*time.Millisecond)
+			for i := 0; i < 3; i++ {
+				Expect(memo(ctx)).To(Equal("foo"))
+			}
+
+			time.Sleep(60 * time.Millisecond)
+			Expect(memo(ctx)).To(Equal("foo"))
+		})
+
+		It("should coalesce concurrent calls on a cold cache", func() {
+			var (
+				f       = mock.NewMockStringFunc(ctrl)
+				ctx     = context.TODO()
+				release = make(chan struct{})
+				wg      sync.WaitGroup
+			)
+
+			f.EXPECT().Call(ctx).DoAndReturn(func(context.Context) (string, error) {
+				<-release
+				return "foo", nil
+			})
+
+			memo := MemoizeString(f.Call, time.Minute)
+			wg.Add(5)
+			for i := 0; i < 5; i++ {
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					Expect(memo(ctx)).To(Equal("foo"))
+				}()
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+			wg.Wait()
+		})
+
+		It("should not cache errors", func() {
+			var (
+				err1 = mkError(1)
+				f    = mock.NewMockStringFunc(ctrl)
+				ctx  = context.TODO()
+			)
+
+			gomock.InOrder(
+				f.EXPECT().Call(ctx).Return("", err1),
+				f.EXPECT().Call(ctx).Return("foo", nil),
+			)
+
+			memo := MemoizeString(f.Call, time.Minute)
+			_, err := memo(ctx)
+			Expect(err).To(BeIdenticalTo(err1))
+			Expect(memo(ctx)).To(Equal("foo"))
+			Expect(memo(ctx)).To(Equal("foo"))
+		})
+
+		It("should fail the waiting calls and invoke the function again after it panicked", func() {
+			var (
+				started = make(chan struct{})
+				release = make(chan struct{})
+				calls   int
+			)
+			memo := MemoizeString(func(context.Context) (string, error) {
+				calls++
+				if calls == 1 {
+					close(started)
+					<-release
+					panic("boom")
+				}
+				return "foo", nil
+			}, time.Minute)
+
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() { panicked <- recover() }()
+				_, _ = memo(context.TODO())
+			}()
+			Eventually(started).Should(BeClosed())
+
+			waited := make(chan error, 1)
+			go func() {
+				_, err := memo(context.TODO())
+				waited <- err
+			}()
+			Consistently(waited).ShouldNot(Receive())
+			close(release)
+
+			Eventually(panicked).Should(Receive(Equal("boom")))
+			var err error
+			Eventually(waited).Should(Receive(&err))
+			Expect(err).To(BeAssignableToTypeOf(&PanicError{}))
+			Expect(err.(*PanicError).Value).To(Equal("boom"))
+
+			Expect(memo(context.TODO())).To(Equal("foo"))
+		})
+	})
+
+	Describe("MemoizeInt", func() {
+		It("should call the function once within the ttl and again after it expired", func() {
+			var (
+				f   = mock.NewMockIntFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			f.EXPECT().Call(ctx).Return(1, nil).Times(2)
+
+			memo := MemoizeInt(f.Call, 50*time.Millisecond)
+			for i := 0; i < 3; i++ {
+				Expect(memo(ctx)).To(Equal(1))
+			}
+
+			time.Sleep(60 * time.Millisecond)
+			Expect(memo(ctx)).To(Equal(1))
+		})
+
+		It("should invoke the function again after it panicked", func() {
+			var (
+				f   = mock.NewMockIntFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			gomock.InOrder(
+				f.EXPECT().Call(ctx).Do(func(context.Context) { panic("boom") }),
+				f.EXPECT().Call(ctx).Return(1, nil),
+			)
+
+			memo := MemoizeInt(f.Call, time.Minute)
+			Expect(func() { _, _ = memo(ctx) }).To(Panic())
+			Expect(memo(ctx)).To(Equal(1))
+		})
+	})
+})
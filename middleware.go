@@ -0,0 +1,123 @@
+package flow
+
+import (
+	"context"
+	"time"
+)
+
+// Middleware decorates a Func with cross-cutting behavior like timeouts or retries.
+type Middleware func(Func) Func
+
+// Use decorates fn with the given middlewares.
+//
+// The first middleware is the outermost one, i.e. Use(fn, a, b) is equivalent to a(b(fn)).
+func Use(fn Func, mws ...Middleware) Func {
+	for i := len(mws) - 1; i >= 0; i-- {
+		fn = mws[i](fn)
+	}
+	return fn
+}
+
+// TimeoutMW creates a Middleware running the Func with a context that expires after d.
+func TimeoutMW(d time.Duration) Middleware {
+	return func(fn Func) Func {
+		return func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return fn(ctx)
+		}
+	}
+}
+
+// Deadline adapts fn to a Func running it with a context that expires at t.
+//
+// If t already passed, fn is not run and context.DeadlineExceeded is returned.
+func Deadline(fn Func, t time.Time) Func {
+	return func(ctx context.Context) error {
+		ctx, cancel, err := deadlineContext(ctx, t)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+		return fn(ctx)
+	}
+}
+
+// DeadlineString adapts fn to a StringFunc running it with a context that expires at t.
+//
+// If t already passed, fn is not run and context.DeadlineExceeded is returned.
+func DeadlineString(fn StringFunc, t time.Time) StringFunc {
+	return func(ctx context.Context) (string, error) {
+		ctx, cancel, err := deadlineContext(ctx, t)
+		if err != nil {
+			return "", err
+		}
+		defer cancel()
+		return fn(ctx)
+	}
+}
+
+// DeadlineInt adapts fn to an IntFunc running it with a context that expires at t.
+//
+// If t already passed, fn is not run and context.DeadlineExceeded is returned.
+func DeadlineInt(fn IntFunc, t time.Time) IntFunc {
+	return func(ctx context.Context) (int, error) {
+		ctx, cancel, err := deadlineContext(ctx, t)
+		if err != nil {
+			return 0, err
+		}
+		defer cancel()
+		return fn(ctx)
+	}
+}
+
+// DeadlineBool adapts fn to a BoolFunc running it with a context that expires at t.
+//
+// If t already passed, fn is not run and context.DeadlineExceeded is returned.
+func DeadlineBool(fn BoolFunc, t time.Time) BoolFunc {
+	return func(ctx context.Context) (bool, error) {
+		ctx, cancel, err := deadlineContext(ctx, t)
+		if err != nil {
+			return false, err
+		}
+		defer cancel()
+		return fn(ctx)
+	}
+}
+
+// deadlineContext derives a context expiring at t from ctx. If t already passed, it returns
+// context.DeadlineExceeded instead.
+func deadlineContext(ctx context.Context, t time.Time) (context.Context, context.CancelFunc, error) {
+	if !time.Now().Before(t) {
+		return nil, nil, context.DeadlineExceeded
+	}
+	ctx, cancel := context.WithDeadline(ctx, t)
+	return ctx, cancel, nil
+}
+
+// RetryMW creates a Middleware running the Func at most attempts times until it succeeds.
+//
+// See Retry for the semantics.
+func RetryMW(attempts int, backoff Backoff) Middleware {
+	return func(fn Func) Func {
+		return func(ctx context.Context) error {
+			return Retry(ctx, fn, attempts, backoff)
+		}
+	}
+}
+
+// RecoverMW creates a Middleware recovering panics of the Func.
+//
+// The recovered value is passed to handler, whose result is returned as the error of the Func.
+func RecoverMW(handler func(interface{}) error) Middleware {
+	return func(fn Func) Func {
+		return func(ctx context.Context) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = handler(r)
+				}
+			}()
+			return fn(ctx)
+		}
+	}
+}
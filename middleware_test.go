@@ -0,0 +1,141 @@
+package flow_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Middleware", func() {
+	Describe("Use", func() {
+		It("should apply the middlewares with the first one being the outermost", func() {
+			var (
+				order []string
+				mw    = func(name string) Middleware {
+					return func(fn Func) Func {
+						return func(ctx context.Context) error {
+							order = append(order, name)
+							return fn(ctx)
+						}
+					}
+				}
+				fn = func(context.Context) error {
+					order = append(order, "fn")
+					return nil
+				}
+			)
+
+			Expect(Use(fn, mw("a"), mw("b"))(context.TODO())).To(Succeed())
+			Expect(order).To(Equal([]string{"a", "b", "fn"}))
+		})
+
+		It("should combine timeout, retry and recover over a flaky function", func() {
+			var (
+				attempts int
+				errs     []error
+				flaky    = func(ctx context.Context) error {
+					attempts++
+					switch attempts {
+					case 1:
+						panic("boom")
+					case 2:
+						<-ctx.Done()
+						return ctx.Err()
+					}
+					return nil
+				}
+				fn = Use(flaky,
+					RetryMW(3, ConstantBackoff(0)),
+					recordMW(&errs),
+					TimeoutMW(10*time.Millisecond),
+					RecoverMW(func(r interface{}) error { return fmt.Errorf("recovered: %v", r) }),
+				)
+			)
+
+			Expect(fn(context.TODO())).To(Succeed())
+			Expect(attempts).To(Equal(3))
+			Expect(errs).To(HaveLen(3))
+			Expect(errs[0]).To(MatchError("recovered: boom"))
+			Expect(errs[1]).To(MatchError(context.DeadlineExceeded))
+			Expect(errs[2]).NotTo(HaveOccurred())
+		})
+
+		It("should give up retrying after the given number of attempts", func() {
+			var (
+				attempts int
+				err1     = mkError(1)
+			)
+
+			err := Use(func(context.Context) error {
+				attempts++
+				return err1
+			}, RetryMW(2, ConstantBackoff(0)))(context.TODO())
+			Expect(err).To(BeIdenticalTo(err1))
+			Expect(attempts).To(Equal(2))
+		})
+	})
+
+	Describe("Deadline", func() {
+		It("should not run the function if the deadline already passed", func() {
+			var ran bool
+			fn := Deadline(func(context.Context) error {
+				ran = true
+				return nil
+			}, time.Now().Add(-time.Second))
+
+			Expect(fn(context.TODO())).To(MatchError(context.DeadlineExceeded))
+			Expect(ran).To(BeFalse())
+		})
+
+		It("should cancel a slow function at the deadline", func() {
+			fn := Deadline(func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Second):
+					return nil
+				}
+			}, time.Now().Add(10*time.Millisecond))
+
+			Expect(fn(context.TODO())).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("should adapt the typed functions", func() {
+			var (
+				future = time.Now().Add(time.Second)
+				past   = time.Now().Add(-time.Second)
+				ctx    = context.TODO()
+			)
+
+			str, err := DeadlineString(func(context.Context) (string, error) { return "a", nil }, future)(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(str).To(Equal("a"))
+
+			n, err := DeadlineInt(func(context.Context) (int, error) { return 1, nil }, past)(ctx)
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(n).To(BeZero())
+
+			ok, err := DeadlineBool(func(ctx context.Context) (bool, error) {
+				_, hasDeadline := ctx.Deadline()
+				return hasDeadline, nil
+			}, future)(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+	})
+})
+
+// recordMW creates a Middleware appending every error of the Func to errs.
+func recordMW(errs *[]error) Middleware {
+	return func(fn Func) Func {
+		return func(ctx context.Context) error {
+			err := fn(ctx)
+			*errs = append(*errs, err)
+			return err
+		}
+	}
+}
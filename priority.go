@@ -0,0 +1,35 @@
+package flow
+
+// WithRacePriority makes Race, RaceString, RaceInt, RaceIntIndexed, RaceBool and RaceAny prefer lower indices on ties.
+//
+// Once the first function completed, the results of all functions that completed by then are
+// considered and the one of the function with the lowest index wins. Without this option, the
+// winner among functions completing at about the same time depends on the scheduling.
+func WithRacePriority() Option {
+	return func(f *Flow) {
+		f.racePriority = true
+	}
+}
+
+// prioritize returns first or, if the Flow has race priority, the result with the lowest index
+// among first and the results that are ready to be received from results.
+func prioritize[T any](f *Flow, results <-chan T, first T, index func(T) int) T {
+	if !f.racePriority {
+		return first
+	}
+
+	best := first
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				return best
+			}
+			if index(res) < index(best) {
+				best = res
+			}
+		default:
+			return best
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package flow_test
+
+import (
+	"context"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// reverseExecutor collects n submissions and then runs them synchronously in reverse order.
+func reverseExecutor(n int) Executor {
+	var submitted []func()
+	return ExecutorFunc(func(f func()) {
+		submitted = append(submitted, f)
+		if len(submitted) == n {
+			for i := n - 1; i >= 0; i-- {
+				submitted[i]()
+			}
+		}
+	})
+}
+
+var _ = Describe("Priority", func() {
+	Describe("WithRacePriority", func() {
+		var (
+			err0 = mkError(0)
+			err1 = mkError(1)
+			fns  = []Func{
+				func(context.Context) error { return err0 },
+				func(context.Context) error { return err1 },
+			}
+		)
+
+		It("should let the function with the lowest index win if both completed", func() {
+			f := New(reverseExecutor(2), WithRacePriority())
+			Expect(f.Race(context.TODO(), fns...)).To(BeIdenticalTo(err0))
+		})
+
+		It("should let the first received result win without the option", func() {
+			f := New(reverseExecutor(2))
+			Expect(f.Race(context.TODO(), fns...)).To(BeIdenticalTo(err1))
+		})
+
+		It("should apply to the typed races", func() {
+			f := New(reverseExecutor(2), WithRacePriority())
+			res, err := f.RaceString(context.TODO(),
+				func(context.Context) (string, error) { return "0", nil },
+				func(context.Context) (string, error) { return "1", nil },
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("0"))
+
+			f = New(reverseExecutor(2), WithRacePriority())
+			n, i, err := f.RaceIntIndexed(context.TODO(),
+				func(context.Context) (int, error) { return 10, nil },
+				func(context.Context) (int, error) { return 11, nil },
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(Equal(10))
+			Expect(i).To(Equal(0))
+		})
+	})
+})
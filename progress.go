@@ -0,0 +1,93 @@
+package flow
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WithProgress makes the Flow report the progress of its parallel executions to cb.
+//
+// While an execution is running, cb is called at most once per interval with the number of
+// completed functions and the total number of functions, if that number changed since the last call.
+// Nil functions count as completed right away. If interval is not positive, only the final
+// progress is reported.
+//
+// Once all functions completed, cb is called a final time. Executions waiting for all their
+// functions, like Parallel, return after that call; executions returning early, like the Race
+// variants, may return before it. Calls to cb for the same execution never happen concurrently.
+func WithProgress(interval time.Duration, cb func(done, total int)) Option {
+	return func(f *Flow) {
+		f.progressInterval = interval
+		f.progressCallback = cb
+	}
+}
+
+// progress tracks the completed functions of a single execution.
+type progress struct {
+	done     int64
+	total    int
+	cb       func(done, total int)
+	stop     chan struct{}
+	finished chan struct{}
+}
+
+// startProgress starts reporting the progress of an execution of total functions.
+//
+// If the Flow has no progress callback configured, nil is returned. All methods of
+// *progress are no-ops on nil.
+func (f *Flow) startProgress(total int) *progress {
+	if f.progressCallback == nil {
+		return nil
+	}
+
+	p := &progress{
+		total:    total,
+		cb:       f.progressCallback,
+		stop:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	go p.report(f.progressInterval)
+	return p
+}
+
+func (p *progress) report(interval time.Duration) {
+	defer close(p.finished)
+
+	if interval <= 0 {
+		<-p.stop
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	reported := int64(-1)
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if done := atomic.LoadInt64(&p.done); done != reported {
+				reported = done
+				p.cb(int(done), p.total)
+			}
+		}
+	}
+}
+
+// complete marks a function as completed.
+func (p *progress) complete() {
+	if p != nil {
+		atomic.AddInt64(&p.done, 1)
+	}
+}
+
+// finish stops the periodic reports and reports the final progress.
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+
+	close(p.stop)
+	<-p.finished
+	p.cb(int(atomic.LoadInt64(&p.done)), p.total)
+}
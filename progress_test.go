@@ -0,0 +1,56 @@
+package flow_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Progress", func() {
+	Describe("WithProgress", func() {
+		It("should report increasing progress ending with all functions done", func() {
+			var (
+				done  []int
+				total []int
+				f     = New(UnlimitedExecutor, WithProgress(time.Millisecond, func(d, t int) {
+					done = append(done, d)
+					total = append(total, t)
+				}))
+				fns = make([]Func, 10)
+			)
+			for i := range fns {
+				d := time.Duration(i) * 2 * time.Millisecond
+				fns[i] = func(context.Context) error {
+					time.Sleep(d)
+					return nil
+				}
+			}
+
+			Expect(f.Parallel(context.TODO(), fns...)).To(Succeed())
+			Expect(done).NotTo(BeEmpty())
+			Expect(done[len(done)-1]).To(Equal(10))
+			for i := 1; i < len(done); i++ {
+				Expect(done[i]).To(BeNumerically(">=", done[i-1]))
+			}
+			for _, t := range total {
+				Expect(t).To(Equal(10))
+			}
+		})
+
+		It("should only report the final progress if the interval is not positive", func() {
+			var calls [][2]int
+			f := New(UnlimitedExecutor, WithProgress(0, func(d, t int) {
+				calls = append(calls, [2]int{d, t})
+			}))
+
+			Expect(f.Parallel(context.TODO(),
+				func(context.Context) error { time.Sleep(5 * time.Millisecond); return nil },
+				func(context.Context) error { return nil },
+			)).To(Succeed())
+			Expect(calls).To(Equal([][2]int{{2, 2}}))
+		})
+	})
+})
@@ -0,0 +1,80 @@
+package flow
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff computes the time to wait before the retry with the given zero-based attempt number.
+type Backoff func(attempt int) time.Duration
+
+// Retry runs fn until it succeeds, at most attempts times.
+//
+// Between the attempts, it waits for the duration computed by backoff. If the context expires while
+// waiting, the context error is returned. Otherwise, the error of the last attempt is returned.
+// fn is always run at least once.
+func Retry(ctx context.Context, fn Func, attempts int, backoff Backoff) error {
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt+1 >= attempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// ConstantBackoff creates a Backoff that always waits d.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff creates a Backoff that waits base, doubling the duration on every attempt up to max.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 0; i < attempt && d < max; i++ {
+			if d > max/2 {
+				return max
+			}
+			d *= 2
+		}
+		if d > max {
+			return max
+		}
+		return d
+	}
+}
+
+// JitteredBackoff creates a Backoff that randomizes the durations of an ExponentialBackoff.
+//
+// The duration of every attempt is chosen randomly between half and the full duration of
+// the corresponding ExponentialBackoff, avoiding many retries happening at the same time.
+// The returned Backoff is safe for concurrent use.
+func JitteredBackoff(base, max time.Duration) Backoff {
+	var (
+		exponential = ExponentialBackoff(base, max)
+		lock        sync.Mutex
+		rnd         = rand.New(rand.NewSource(time.Now().UnixNano()))
+	)
+	return func(attempt int) time.Duration {
+		d := exponential(attempt)
+		if d <= 1 {
+			return d
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		return d/2 + time.Duration(rnd.Int63n(int64(d-d/2)+1))
+	}
+}
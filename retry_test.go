@@ -0,0 +1,94 @@
+package flow_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/adracus/flow"
+	"github.com/adracus/flow/mock"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func backoffs(backoff Backoff, attempts int) []time.Duration {
+	out := make([]time.Duration, attempts)
+	for i := range out {
+		out[i] = backoff(i)
+	}
+	return out
+}
+
+var _ = Describe("Retry", func() {
+	var ctrl *gomock.Controller
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("Retry", func() {
+		It("should retry the function until it succeeds", func() {
+			var (
+				f   = mock.NewMockFunc(ctrl)
+				ctx = context.TODO()
+			)
+
+			gomock.InOrder(
+				f.EXPECT().Call(ctx).Return(mkError(1)),
+				f.EXPECT().Call(ctx).Return(mkError(2)),
+				f.EXPECT().Call(ctx),
+			)
+
+			Expect(Retry(ctx, f.Call, 5, ConstantBackoff(time.Millisecond))).To(Succeed())
+		})
+
+		It("should return the last error if all attempts fail", func() {
+			var (
+				err2 = mkError(2)
+				f    = mock.NewMockFunc(ctrl)
+				ctx  = context.TODO()
+			)
+
+			gomock.InOrder(
+				f.EXPECT().Call(ctx).Return(mkError(1)),
+				f.EXPECT().Call(ctx).Return(err2),
+			)
+
+			Expect(Retry(ctx, f.Call, 2, ConstantBackoff(time.Millisecond))).To(BeIdenticalTo(err2))
+		})
+	})
+
+	Describe("ConstantBackoff", func() {
+		It("should always return the same duration", func() {
+			Expect(backoffs(ConstantBackoff(time.Second), 6)).To(Equal([]time.Duration{
+				time.Second, time.Second, time.Second, time.Second, time.Second, time.Second,
+			}))
+		})
+	})
+
+	Describe("ExponentialBackoff", func() {
+		It("should double the duration up to the maximum", func() {
+			Expect(backoffs(ExponentialBackoff(time.Second, 10*time.Second), 6)).To(Equal([]time.Duration{
+				time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second,
+			}))
+		})
+	})
+
+	Describe("JitteredBackoff", func() {
+		It("should stay within half and the full exponential duration", func() {
+			var (
+				exponential = backoffs(ExponentialBackoff(time.Second, 10*time.Second), 6)
+				jittered    = JitteredBackoff(time.Second, 10*time.Second)
+			)
+
+			for i := 0; i < 100; i++ {
+				for attempt, d := range backoffs(jittered, 6) {
+					Expect(d).To(BeNumerically(">=", exponential[attempt]/2))
+					Expect(d).To(BeNumerically("<=", exponential[attempt]))
+				}
+			}
+		})
+	})
+})
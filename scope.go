@@ -0,0 +1,151 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Scope tracks dynamically spawned functions and collects their errors.
+//
+// Functions may be spawned before Wait is called or from within other functions of the Scope.
+// Spawning after Wait has returned panics.
+type Scope struct {
+	ctx      context.Context
+	flow     *Flow
+	executor Executor
+
+	lock sync.Mutex
+	// idle is signalled once running drops to zero.
+	idle    sync.Cond
+	running int
+	errs    multiError
+	waited  bool
+}
+
+// NewScope creates a new Scope whose functions are run with the given context.
+func (f *Flow) NewScope(ctx context.Context) *Scope {
+	s := &Scope{ctx: ctx, flow: f, executor: f.executorFor(ctx)}
+	s.idle.L = &s.lock
+	return s
+}
+
+// Spawn launches fn as part of the Scope.
+func (s *Scope) Spawn(fn Func) {
+	if fn == nil {
+		return
+	}
+
+	s.lock.Lock()
+	if s.waited {
+		s.lock.Unlock()
+		panic(errors.New("spawn after the scope was waited for"))
+	}
+	s.running++
+	s.lock.Unlock()
+
+	s.executor.Submit(func() {
+		var err error
+		defer func() {
+			s.lock.Lock()
+			defer s.lock.Unlock()
+			if err != nil {
+				s.errs = append(s.errs, err)
+			}
+			if s.running--; s.running == 0 {
+				s.idle.Broadcast()
+			}
+		}()
+		err = fn(s.ctx)
+	})
+}
+
+// Wait blocks until all spawned functions have completed.
+//
+// It collects all the errors in the returned error. To obtain
+// the multiple errors, use the `Errors` function.
+func (s *Scope) Wait() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Functions are only counted while holding the lock, so no function can be spawned between
+	// the last one completing and marking the scope as waited for.
+	for s.running > 0 {
+		s.idle.Wait()
+	}
+	s.waited = true
+	return s.flow.aggregate(s.errs)
+}
+
+// Nursery tracks dynamically spawned functions, cancelling all of them once one fails.
+//
+// It is the dynamic counterpart of ParallelCancelOnError: functions may be spawned before Wait is
+// called or from within other functions of the Nursery. Spawning after Wait has returned panics.
+type Nursery struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	executor Executor
+
+	lock sync.Mutex
+	// idle is signalled once running drops to zero.
+	idle    sync.Cond
+	running int
+	err     error
+	waited  bool
+}
+
+// NewNursery creates a new Nursery whose functions are run with a context derived from ctx.
+//
+// The derived context is cancelled the first time a function of the Nursery fails or once Wait
+// returns, whichever occurs first.
+func (f *Flow) NewNursery(ctx context.Context) *Nursery {
+	ctx, cancel := context.WithCancel(ctx)
+	n := &Nursery{ctx: ctx, cancel: cancel, executor: f.executorFor(ctx)}
+	n.idle.L = &n.lock
+	return n
+}
+
+// Go launches fn as part of the Nursery.
+func (n *Nursery) Go(fn Func) {
+	if fn == nil {
+		return
+	}
+
+	n.lock.Lock()
+	if n.waited {
+		n.lock.Unlock()
+		panic(errors.New("spawn after the nursery was waited for"))
+	}
+	n.running++
+	n.lock.Unlock()
+
+	n.executor.Submit(func() {
+		var err error
+		defer func() {
+			n.lock.Lock()
+			defer n.lock.Unlock()
+			if err != nil && n.err == nil {
+				n.err = err
+				n.cancel()
+			}
+			if n.running--; n.running == 0 {
+				n.idle.Broadcast()
+			}
+		}()
+		err = fn(n.ctx)
+	})
+}
+
+// Wait blocks until all spawned functions have completed, then returns the first error, if any.
+func (n *Nursery) Wait() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	// See Scope.Wait.
+	for n.running > 0 {
+		n.idle.Wait()
+	}
+	n.waited = true
+	n.cancel()
+	return n.err
+}
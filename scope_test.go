@@ -0,0 +1,142 @@
+package flow_test
+
+import (
+	"context"
+	"sync/atomic"
+
+	. "github.com/adracus/flow"
+	"github.com/adracus/flow/mock"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scope", func() {
+	var ctrl *gomock.Controller
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should wait for nested spawned functions and collect their errors", func() {
+		var (
+			err2 = mkError(2)
+			err3 = mkError(3)
+			f1   = mock.NewMockFunc(ctrl)
+			f2   = mock.NewMockFunc(ctrl)
+			f3   = mock.NewMockFunc(ctrl)
+
+			ctx   = context.TODO()
+			scope = NewScope(ctx)
+		)
+
+		f1.EXPECT().Call(ctx).DoAndReturn(func(ctx context.Context) error {
+			scope.Spawn(f2.Call)
+			return nil
+		})
+		f2.EXPECT().Call(ctx).DoAndReturn(func(ctx context.Context) error {
+			scope.Spawn(f3.Call)
+			return err2
+		})
+		f3.EXPECT().Call(ctx).Return(err3)
+
+		scope.Spawn(f1.Call)
+		err := scope.Wait()
+		Expect(err).To(HaveOccurred())
+		Expect(Errors(err)).To(ConsistOf(err2, err3))
+	})
+
+	It("should reject spawning after Wait", func() {
+		scope := NewScope(context.TODO())
+		Expect(scope.Wait()).To(Succeed())
+		Expect(func() { scope.Spawn(func(context.Context) error { return nil }) }).To(Panic())
+	})
+
+	It("should either wait for a function spawned concurrently to Wait or reject it", func() {
+		for i := 0; i < 100; i++ {
+			var (
+				scope   = NewScope(context.TODO())
+				ran     int32
+				spawned = make(chan bool, 1)
+			)
+			go func() {
+				defer func() { spawned <- recover() == nil }()
+				scope.Spawn(func(context.Context) error {
+					atomic.StoreInt32(&ran, 1)
+					return nil
+				})
+			}()
+
+			Expect(scope.Wait()).To(Succeed())
+			ranBeforeWait := atomic.LoadInt32(&ran) == 1
+			Expect(<-spawned).To(Equal(ranBeforeWait))
+		}
+	})
+})
+
+var _ = Describe("Nursery", func() {
+	It("should cancel the other functions once one fails and return its error", func() {
+		var (
+			err1    = mkError(1)
+			nursery = NewNursery(context.TODO())
+			started = make(chan struct{}, 2)
+			errs    = make(chan error, 2)
+		)
+
+		for i := 0; i < 2; i++ {
+			nursery.Go(func(ctx context.Context) error {
+				started <- struct{}{}
+				<-ctx.Done()
+				errs <- ctx.Err()
+				return ctx.Err()
+			})
+		}
+		nursery.Go(func(ctx context.Context) error {
+			<-started
+			<-started
+			return err1
+		})
+
+		Expect(nursery.Wait()).To(BeIdenticalTo(err1))
+		Expect(errs).To(Receive(Equal(context.Canceled)))
+		Expect(errs).To(Receive(Equal(context.Canceled)))
+	})
+
+	It("should return nil if all functions succeed", func() {
+		nursery := NewNursery(context.TODO())
+		nursery.Go(func(ctx context.Context) error {
+			nursery.Go(func(ctx context.Context) error { return nil })
+			return nil
+		})
+		Expect(nursery.Wait()).To(Succeed())
+	})
+
+	It("should reject spawning after Wait", func() {
+		nursery := NewNursery(context.TODO())
+		Expect(nursery.Wait()).To(Succeed())
+		Expect(func() { nursery.Go(func(context.Context) error { return nil }) }).To(Panic())
+	})
+
+	It("should either wait for a function spawned concurrently to Wait or reject it", func() {
+		for i := 0; i < 100; i++ {
+			var (
+				nursery = NewNursery(context.TODO())
+				err1    = mkError(1)
+				spawned = make(chan bool, 1)
+			)
+			go func() {
+				defer func() { spawned <- recover() == nil }()
+				nursery.Go(func(context.Context) error { return err1 })
+			}()
+
+			err := nursery.Wait()
+			if <-spawned {
+				Expect(err).To(BeIdenticalTo(err1))
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+	})
+})
@@ -0,0 +1,70 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// semaphore bounds the number of concurrently acquired slots.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	return make(semaphore, n)
+}
+
+// acquire blocks until a slot is free or the context is done.
+func (s semaphore) acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		if err := ctx.Err(); err != nil {
+			s.release()
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	<-s
+}
+
+// Mutex is a mutual exclusion lock whose locking can be cancelled.
+//
+// The zero value is an unlocked Mutex. A Mutex must not be copied after first use.
+type Mutex struct {
+	once sync.Once
+	sem  semaphore
+}
+
+// NewMutex creates a new unlocked Mutex.
+func NewMutex() *Mutex {
+	return &Mutex{}
+}
+
+// semaphore returns the semaphore of the Mutex, allocating it on first use.
+func (m *Mutex) semaphore() semaphore {
+	m.once.Do(func() {
+		m.sem = newSemaphore(1)
+	})
+	return m.sem
+}
+
+// Lock blocks until the Mutex is locked or the context is done.
+//
+// If the context is done before the Mutex could be locked, the context error is returned and the
+// Mutex is not locked.
+func (m *Mutex) Lock(ctx context.Context) error {
+	return m.semaphore().acquire(ctx)
+}
+
+// Unlock unlocks the Mutex. It panics if the Mutex is not locked.
+func (m *Mutex) Unlock() {
+	select {
+	case <-m.semaphore():
+	default:
+		panic(errors.New("unlock of unlocked mutex"))
+	}
+}
@@ -0,0 +1,65 @@
+package flow_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mutex", func() {
+	It("should guard shared state of contending functions", func() {
+		var (
+			mu      = NewMutex()
+			counter int
+			fns     = make([]Func, 50)
+		)
+		for i := range fns {
+			fns[i] = func(ctx context.Context) error {
+				if err := mu.Lock(ctx); err != nil {
+					return err
+				}
+				defer mu.Unlock()
+
+				counter++
+				return nil
+			}
+		}
+
+		Expect(Parallel(context.TODO(), fns...)).To(Succeed())
+		Expect(counter).To(Equal(len(fns)))
+	})
+
+	It("should return the context error if cancelled while waiting for the lock", func() {
+		var (
+			mu          = NewMutex()
+			ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+		)
+		defer cancel()
+
+		Expect(mu.Lock(context.TODO())).To(Succeed())
+		Expect(mu.Lock(ctx)).To(MatchError(context.DeadlineExceeded))
+
+		mu.Unlock()
+		Expect(mu.Lock(context.TODO())).To(Succeed())
+	})
+
+	It("should panic when unlocking an unlocked mutex", func() {
+		Expect(NewMutex().Unlock).To(Panic())
+	})
+
+	It("should be usable as zero value", func() {
+		var mu Mutex
+		Expect(mu.Unlock).To(Panic())
+
+		Expect(mu.Lock(context.TODO())).To(Succeed())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(mu.Lock(ctx)).To(MatchError(context.Canceled))
+
+		mu.Unlock()
+		Expect(mu.Lock(context.TODO())).To(Succeed())
+	})
+})
@@ -0,0 +1,131 @@
+package flow
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// OverflowPolicy determines how a stream behaves if its buffer is full.
+type OverflowPolicy int
+
+const (
+	// Block makes the functions wait until the consumer received a result, limiting them to the pace of the consumer.
+	Block OverflowPolicy = iota
+	// DropOldest makes the functions discard the oldest buffered result, so they never wait for the consumer.
+	DropOldest
+)
+
+type streamConfig struct {
+	buffer int
+	onFull OverflowPolicy
+}
+
+// StreamOption configures a stream.
+type StreamOption func(*streamConfig)
+
+// StreamBuffer sets the number of results a stream buffers for its consumer.
+//
+// By default, results are not buffered. With DropOldest, at least one result is buffered.
+func StreamBuffer(n int) StreamOption {
+	return func(c *streamConfig) {
+		c.buffer = n
+	}
+}
+
+// StreamOnFull sets the behavior of a stream if its buffer is full. The default is Block.
+func StreamOnFull(policy OverflowPolicy) StreamOption {
+	return func(c *streamConfig) {
+		c.onFull = policy
+	}
+}
+
+// StringResult is the result of a single function of a stream.
+type StringResult struct {
+	// Index is the index of the function.
+	Index int
+	// Value is the result of the function.
+	Value string
+	// Err is the error returned by the function.
+	Err error
+}
+
+// StringStream streams the results of the functions run by ParallelStringStream.
+type StringStream struct {
+	results <-chan StringResult
+	dropped int64 // accessed atomically
+}
+
+// Results returns the channel of the results. It is closed once all functions completed.
+func (s *StringStream) Results() <-chan StringResult {
+	return s.results
+}
+
+// Dropped returns the number of results that were discarded because the buffer was full.
+func (s *StringStream) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// ParallelStringStream runs the given functions in parallel, streaming their results as they complete.
+//
+// With Block, functions whose results cannot be delivered wait for the consumer or until the
+// context is done, in which case their results are discarded. With DropOldest, the oldest buffered
+// result is discarded in favor of the new one; use Dropped to obtain the number of discarded results.
+// If the Flow rejects the execution, e.g. because its maximum fan-out is exceeded, none of the
+// functions is run and the stream delivers a single result with index -1 holding that error.
+func (f *Flow) ParallelStringStream(ctx context.Context, fns []StringFunc, opts ...StreamOption) *StringStream {
+	var cfg streamConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.onFull == DropOldest && cfg.buffer < 1 {
+		cfg.buffer = 1
+	}
+
+	var (
+		results = make(chan StringResult, cfg.buffer)
+		s       = &StringStream{results: results}
+	)
+	if len(fns) == 0 {
+		close(results)
+		return s
+	}
+	if err := f.check(ctx, len(fns)); err != nil {
+		rejected := make(chan StringResult, 1)
+		rejected <- StringResult{Index: -1, Err: err}
+		close(rejected)
+		s.results = rejected
+		return s
+	}
+
+	f.runAll(ctx, len(fns), func(ctx context.Context, i int) {
+		fn := fns[i]
+		if fn == nil {
+			return
+		}
+
+		item, err := fn(ctx)
+		res := StringResult{i, item, err}
+		if cfg.onFull == Block {
+			select {
+			case results <- res:
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		for {
+			select {
+			case results <- res:
+				return
+			default:
+			}
+
+			select {
+			case <-results:
+				atomic.AddInt64(&s.dropped, 1)
+			default:
+			}
+		}
+	}, func() { close(results) })
+	return s
+}
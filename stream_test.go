@@ -0,0 +1,67 @@
+package flow_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stream", func() {
+	var (
+		completed int32
+		fns       []StringFunc
+	)
+	BeforeEach(func() {
+		completed = 0
+		fns = make([]StringFunc, 5)
+		for i := range fns {
+			i := i
+			fns[i] = func(context.Context) (string, error) {
+				atomic.AddInt32(&completed, 1)
+				return fmt.Sprint(i), nil
+			}
+		}
+	})
+
+	collect := func(s *StringStream, delay time.Duration) []string {
+		var out []string
+		for res := range s.Results() {
+			time.Sleep(delay)
+			out = append(out, res.Value)
+		}
+		return out
+	}
+
+	Describe("ParallelStringStream", func() {
+		It("should make the functions wait for a slow consumer with Block", func() {
+			s := ParallelStringStream(context.TODO(), fns, StreamBuffer(1), StreamOnFull(Block))
+
+			Eventually(func() int32 { return atomic.LoadInt32(&completed) }).Should(Equal(int32(5)))
+			Consistently(func() int { return len(s.Results()) }).Should(Equal(1))
+
+			Expect(collect(s, 5*time.Millisecond)).To(ConsistOf("0", "1", "2", "3", "4"))
+			Expect(s.Dropped()).To(BeZero())
+		})
+
+		It("should discard the oldest results for a slow consumer with DropOldest", func() {
+			s := ParallelStringStream(context.TODO(), fns, StreamBuffer(2), StreamOnFull(DropOldest))
+
+			Eventually(s.Dropped).Should(Equal(int64(3)))
+			Expect(collect(s, 5*time.Millisecond)).To(HaveLen(2))
+		})
+
+		It("should stop delivering results once the context is done with Block", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			s := ParallelStringStream(ctx, fns)
+
+			Eventually(func() int32 { return atomic.LoadInt32(&completed) }).Should(Equal(int32(5)))
+			cancel()
+			Eventually(s.Results()).Should(BeClosed())
+		})
+	})
+})
@@ -0,0 +1,56 @@
+package flow
+
+import (
+	"context"
+	"strconv"
+)
+
+// WithTracer makes the Flow run the functions of Parallel, Sequence and the methods built on them in
+// spans started by startSpan.
+//
+// Besides Parallel and Sequence, these are ParallelOn, ParallelBG, ParallelFunc, ParallelHandle,
+// ParallelTimeouts and Stages as well as SequenceFunc, SequenceRecover and SequenceBudget. All other
+// methods, e.g. the typed variants like ParallelString or Race, run their functions as is, and so
+// do the package-level functions, which use Default.
+//
+// startSpan is called with the context of the function and the name of the span and returns the
+// context to run the function with as well as a function to finish the span, which is called
+// with the error of the function. The span of the i-th function is named "flow.parallel.i"
+// or "flow.sequence.i", respectively; use Named to add a span with a custom name.
+func WithTracer(startSpan func(ctx context.Context, name string) (context.Context, func(error))) Option {
+	return func(f *Flow) {
+		f.startSpan = startSpan
+	}
+}
+
+type tracerKey struct{}
+
+// Named wraps fn so that it runs in a span with the given name if it is run by a Flow with a tracer.
+//
+// The span is a child of the span the Flow started for the function. Outside of a Flow with a
+// tracer, fn is run as is.
+func Named(name string, fn Func) Func {
+	return func(ctx context.Context) error {
+		startSpan, ok := ctx.Value(tracerKey{}).(func(context.Context, string) (context.Context, func(error)))
+		if !ok {
+			return fn(ctx)
+		}
+		return traced(ctx, startSpan, name, fn)
+	}
+}
+
+// trace runs fn in a span named after the kind of the execution and index, if the Flow has a tracer.
+func (f *Flow) trace(ctx context.Context, kind string, index int, fn Func) error {
+	if f.startSpan == nil {
+		return fn(ctx)
+	}
+	ctx = context.WithValue(ctx, tracerKey{}, f.startSpan)
+	return traced(ctx, f.startSpan, "flow."+kind+"."+strconv.Itoa(index), fn)
+}
+
+func traced(ctx context.Context, startSpan func(context.Context, string) (context.Context, func(error)), name string, fn Func) error {
+	ctx, finish := startSpan(ctx, name)
+	err := fn(ctx)
+	finish(err)
+	return err
+}
@@ -0,0 +1,77 @@
+package flow_test
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/adracus/flow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeTracer struct {
+	lock  sync.Mutex
+	spans map[string]error
+}
+
+func (t *fakeTracer) StartSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	return ctx, func(err error) {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		t.spans[name] = err
+	}
+}
+
+var _ = Describe("Tracer", func() {
+	var (
+		tracer *fakeTracer
+		f      *Flow
+	)
+	BeforeEach(func() {
+		tracer = &fakeTracer{spans: make(map[string]error)}
+		f = New(UnlimitedExecutor, WithTracer(tracer.StartSpan))
+	})
+
+	Describe("WithTracer", func() {
+		It("should run every function of Parallel in a span", func() {
+			err1 := mkError(1)
+
+			err := f.Parallel(context.TODO(),
+				func(context.Context) error { return nil },
+				func(context.Context) error { return err1 },
+			)
+			Expect(Errors(err)).To(ConsistOf(err1))
+			Expect(tracer.spans).To(Equal(map[string]error{
+				"flow.parallel.0": nil,
+				"flow.parallel.1": err1,
+			}))
+		})
+
+		It("should run every function of Sequence in a span", func() {
+			err1 := mkError(1)
+
+			err := f.Sequence(context.TODO(),
+				func(context.Context) error { return nil },
+				func(context.Context) error { return err1 },
+			)
+			Expect(err).To(Equal(err1))
+			Expect(tracer.spans).To(Equal(map[string]error{
+				"flow.sequence.0": nil,
+				"flow.sequence.1": err1,
+			}))
+		})
+	})
+
+	Describe("Named", func() {
+		It("should run the function in a span with the given name", func() {
+			Expect(f.Parallel(context.TODO(), Named("fetch", func(context.Context) error { return nil }))).To(Succeed())
+			Expect(tracer.spans).To(HaveKey("fetch"))
+			Expect(tracer.spans).To(HaveKey("flow.parallel.0"))
+		})
+
+		It("should not start a span outside of a Flow with a tracer", func() {
+			Expect(Parallel(context.TODO(), Named("fetch", func(context.Context) error { return nil }))).To(Succeed())
+			Expect(tracer.spans).To(BeEmpty())
+		})
+	})
+})